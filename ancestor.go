@@ -0,0 +1,69 @@
+package routine
+
+import "sync"
+
+const (
+	maxAncestors    = 16
+	ancestorsShards = 64
+)
+
+// ancestorsShard holds the ancestors of the tasks running in the goroutines, the goroutines are identified by goid.
+type ancestorsShard struct {
+	mutex     sync.Mutex
+	ancestors map[uint64][]Ancestor
+}
+
+var ancestorsRegistry [ancestorsShards]ancestorsShard
+
+// currentAncestors returns the ancestors of the current goroutine or nil if not recorded, the returned slice must not be modified.
+func currentAncestors() []Ancestor {
+	goid := Goid()
+	shard := &ancestorsRegistry[goid%ancestorsShards]
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+	return shard.ancestors[goid]
+}
+
+// swapAncestors records the ancestors of the task running in the goroutine and returns the previous ones, nil ancestors removes the record.
+// The ancestors are kept by the task instead of the threadLocals, so that no thread is allocated for the goroutine.
+func swapAncestors(goid uint64, ancestors []Ancestor) []Ancestor {
+	shard := &ancestorsRegistry[goid%ancestorsShards]
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+	previous := shard.ancestors[goid]
+	if ancestors == nil {
+		delete(shard.ancestors, goid)
+		return previous
+	}
+	if shard.ancestors == nil {
+		shard.ancestors = make(map[uint64][]Ancestor)
+	}
+	shard.ancestors[goid] = ancestors
+	return previous
+}
+
+// copyAncestors returns a copy of the ancestors, so that the ancestors recorded can not be modified by the caller.
+func copyAncestors(ancestors []Ancestor) []Ancestor {
+	if ancestors == nil {
+		return nil
+	}
+	result := make([]Ancestor, len(ancestors))
+	copy(result, ancestors)
+	return result
+}
+
+// newAncestors returns the ancestors of the descendant goroutine, skip is the number of frames to skip before recording the call site, 0 identifying the caller of newAncestors.
+func newAncestors(skip int) []Ancestor {
+	var pc uintptr
+	if pcs := captureStackTrace(skip+1, 1); len(pcs) > 0 {
+		pc = pcs[0]
+	}
+	parent := currentAncestors()
+	if len(parent) >= maxAncestors {
+		parent = parent[:maxAncestors-1]
+	}
+	ancestors := make([]Ancestor, len(parent)+1)
+	ancestors[0] = Ancestor{Goid: Goid(), Pc: pc}
+	copy(ancestors[1:], parent)
+	return ancestors
+}
@@ -0,0 +1,62 @@
+package routine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurrentAncestors(t *testing.T) {
+	assert.Nil(t, currentAncestors())
+	task := GoWait(func(token CancelToken) {
+		ancestors := currentAncestors()
+		assert.Equal(t, 1, len(ancestors))
+		assert.Nil(t, currentThread(false))
+	})
+	task.Get()
+	assert.Nil(t, currentAncestors())
+}
+
+func TestSwapAncestors(t *testing.T) {
+	goid := Goid()
+	ancestors := []Ancestor{{Goid: 1}}
+	assert.Nil(t, swapAncestors(goid, ancestors))
+	assert.Equal(t, ancestors, currentAncestors())
+	ancestors2 := []Ancestor{{Goid: 2}}
+	assert.Equal(t, ancestors, swapAncestors(goid, ancestors2))
+	assert.Equal(t, ancestors2, currentAncestors())
+	assert.Equal(t, ancestors2, swapAncestors(goid, nil))
+	assert.Nil(t, currentAncestors())
+	assert.Nil(t, swapAncestors(goid, nil))
+}
+
+func TestNewAncestors(t *testing.T) {
+	ancestors := newAncestors(0)
+	assert.Equal(t, 1, len(ancestors))
+	assert.Equal(t, Goid(), ancestors[0].Goid)
+	assert.Equal(t, "github.com/timandy/routine.TestNewAncestors", ancestors[0].Frame().Function)
+	assert.Equal(t, 34, ancestors[0].Frame().Line)
+	//
+	task := GoWait(func(token CancelToken) {
+		for i := 0; i < maxAncestors*2; i++ {
+			ancestors := newAncestors(0)
+			assert.LessOrEqual(t, len(ancestors), maxAncestors)
+			swapAncestors(Goid(), ancestors)
+		}
+		ancestors := currentAncestors()
+		assert.Equal(t, maxAncestors, len(ancestors))
+		for _, ancestor := range ancestors {
+			assert.Equal(t, Goid(), ancestor.Goid)
+		}
+	})
+	task.Get()
+}
+
+func TestCopyAncestors(t *testing.T) {
+	assert.Nil(t, copyAncestors(nil))
+	ancestors := []Ancestor{{Goid: 1}, {Goid: 2}}
+	result := copyAncestors(ancestors)
+	assert.Equal(t, ancestors, result)
+	result[0].Goid = 3
+	assert.Equal(t, uint64(1), ancestors[0].Goid)
+}
@@ -0,0 +1,25 @@
+package routine
+
+import "runtime"
+
+// Ancestor represents a goroutine which started the current goroutine directly or indirectly.
+// The ancestors are recorded by Go, GoWait, GoWaitResult methods and the tasks created by WrapTask, WrapWaitTask, WrapWaitResultTask methods.
+type Ancestor struct {
+	// Goid is the goid of the ancestor goroutine.
+	Goid uint64
+
+	// Pc is the pc of the call site in the ancestor goroutine which started the descendant goroutine.
+	Pc uintptr
+}
+
+// Frame resolves the call site which started the descendant goroutine.
+func (a Ancestor) Frame() Frame {
+	frame, _ := runtime.CallersFrames([]uintptr{a.Pc}).Next()
+	return newFrame(frame, false)
+}
+
+// Ancestors returns the ancestors of the current goroutine, the parent comes first.
+// At most 16 levels are recorded, the farther ancestors are dropped.
+func Ancestors() []Ancestor {
+	return copyAncestors(currentAncestors())
+}
@@ -0,0 +1,66 @@
+package routine
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAncestor_Frame(t *testing.T) {
+	ancestor := Ancestor{Goid: Goid(), Pc: captureStackTrace(0, 1)[0]}
+	frame := ancestor.Frame()
+	assert.Equal(t, "github.com/timandy/routine.TestAncestor_Frame", frame.Function)
+	assert.Equal(t, "github.com/timandy/routine", frame.Package)
+	assert.True(t, strings.HasSuffix(frame.File, "api_ancestor_test.go"))
+	assert.Equal(t, 11, frame.Line)
+	assert.False(t, frame.Hidden)
+}
+
+func TestAncestors(t *testing.T) {
+	assert.Nil(t, Ancestors())
+	goid := Goid()
+	task := GoWait(func(token CancelToken) {
+		ancestors := Ancestors()
+		assert.Equal(t, 1, len(ancestors))
+		assert.Equal(t, goid, ancestors[0].Goid)
+		assert.Equal(t, "github.com/timandy/routine.TestAncestors", ancestors[0].Frame().Function)
+		assert.Equal(t, 23, ancestors[0].Frame().Line)
+		//
+		goid2 := Goid()
+		task2 := GoWaitResult(func(token CancelToken) []Ancestor {
+			return Ancestors()
+		})
+		ancestors2 := task2.Get()
+		assert.Equal(t, 2, len(ancestors2))
+		assert.Equal(t, goid2, ancestors2[0].Goid)
+		assert.Equal(t, 31, ancestors2[0].Frame().Line)
+		assert.Equal(t, ancestors[0], ancestors2[1])
+		//
+		ancestors[0] = Ancestor{}
+		assert.Equal(t, goid, Ancestors()[0].Goid)
+	})
+	task.Get()
+}
+
+func TestAncestors_WrapTask(t *testing.T) {
+	goid := Goid()
+	task := WrapWaitResultTask(func(token CancelToken) []Ancestor {
+		return Ancestors()
+	})
+	go task.Run()
+	ancestors := task.Get()
+	assert.Equal(t, 1, len(ancestors))
+	assert.Equal(t, goid, ancestors[0].Goid)
+	assert.Equal(t, "github.com/timandy/routine.TestAncestors_WrapTask", ancestors[0].Frame().Function)
+	assert.Equal(t, 48, ancestors[0].Frame().Line)
+	//
+	executor := NewFixedExecutor(1, 0)
+	defer executor.Shutdown()
+	task2 := SubmitCallable(executor, func() []Ancestor {
+		return Ancestors()
+	})
+	ancestors2 := task2.Get()
+	assert.Equal(t, "github.com/timandy/routine.TestAncestors_WrapTask", ancestors2[0].Frame().Function)
+	assert.Equal(t, 60, ancestors2[0].Frame().Line)
+}
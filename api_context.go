@@ -0,0 +1,39 @@
+package routine
+
+import "context"
+
+// WithContext stores the ctx into the current goroutine's inheritableThreadLocals.
+// The ctx can be inherited to sub goroutines witch started by Go, GoWait, GoWaitResult methods.
+// The task started by GoWait, GoWaitResult methods will be canceled when the ctx is done.
+func WithContext(ctx context.Context) {
+	if ctx == nil {
+		panic("ctx can not be nil.")
+	}
+	contextThreadLocal.Set(ctx)
+}
+
+// Context returns the context stored in the current goroutine, if there is no context, return context.Background().
+func Context() context.Context {
+	if ctx := currentContext(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
+
+// ExportContext returns a copy of parent in which the threadLocals and inheritableThreadLocals of the current goroutine are carried.
+func ExportContext(parent context.Context) context.Context {
+	return context.WithValue(parent, snapshotContextKey{}, CaptureAll())
+}
+
+// ImportContext restores the threadLocals and inheritableThreadLocals carried by ctx into the current goroutine and stores the ctx, returns a function to undo it.
+// If there is nothing carried by ctx, the current goroutine's context will be kept as is, the values set after that will be discarded when undo.
+func ImportContext(ctx context.Context) func() {
+	var undo func()
+	if snapshot, ok := ctx.Value(snapshotContextKey{}).(Snapshot); ok {
+		undo = snapshot.Restore()
+	} else {
+		undo = restoreCurrentMaps()
+	}
+	WithContext(ctx)
+	return undo
+}
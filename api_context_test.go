@@ -0,0 +1,130 @@
+package routine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type contextKey struct{}
+
+func TestWithContext(t *testing.T) {
+	assert.Panics(t, func() {
+		WithContext(nil) //nolint:staticcheck
+	})
+	//
+	task := GoWait(func(token CancelToken) {
+		assert.Equal(t, context.Background(), Context())
+		ctx := context.WithValue(context.Background(), contextKey{}, "Hello")
+		WithContext(ctx)
+		assert.Equal(t, "Hello", Context().Value(contextKey{}))
+		//
+		task2 := GoWait(func(token CancelToken) {
+			assert.Equal(t, "Hello", Context().Value(contextKey{}))
+		})
+		task2.Get()
+	})
+	task.Get()
+}
+
+func TestWithContext_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	task := GoWait(func(token CancelToken) {
+		WithContext(ctx)
+		task2 := GoWait(func(token CancelToken) {
+			for i := 0; i < 100 && !token.IsCanceled(); i++ {
+				time.Sleep(10 * time.Millisecond)
+			}
+			assert.True(t, token.IsCanceled())
+		})
+		task3 := GoWaitResult(func(token CancelToken) int {
+			for i := 0; i < 100 && !token.IsCanceled(); i++ {
+				time.Sleep(10 * time.Millisecond)
+			}
+			assert.True(t, token.IsCanceled())
+			return 1
+		})
+		cancel()
+		assert.Panics(t, func() {
+			task2.Get()
+		})
+		assert.Panics(t, func() {
+			task3.Get()
+		})
+		assert.True(t, task2.IsCanceled())
+		assert.True(t, task3.IsCanceled())
+	})
+	task.Get()
+}
+
+func TestExportContext(t *testing.T) {
+	tls := NewThreadLocal[string]()
+	inheritableTls := NewInheritableThreadLocal[string]()
+	tls.Set("Hello")
+	inheritableTls.Set("World")
+	ctx := ExportContext(context.WithValue(context.Background(), contextKey{}, "!"))
+	//
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert.Equal(t, "", tls.Get())
+		assert.Equal(t, "", inheritableTls.Get())
+		undo := ImportContext(ctx)
+		assert.Equal(t, "Hello", tls.Get())
+		assert.Equal(t, "World", inheritableTls.Get())
+		assert.Same(t, ctx, Context())
+		assert.Equal(t, "!", Context().Value(contextKey{}))
+		undo()
+		assert.Equal(t, "", tls.Get())
+		assert.Equal(t, "", inheritableTls.Get())
+		assert.Equal(t, context.Background(), Context())
+	}()
+	<-done
+}
+
+func TestImportContext(t *testing.T) {
+	tls := NewInheritableThreadLocal[string]()
+	tls.Set("Hello")
+	ctx := context.WithValue(context.Background(), contextKey{}, "!")
+	undo := ImportContext(ctx)
+	assert.Equal(t, "Hello", tls.Get())
+	assert.Same(t, ctx, Context())
+	tls.Set("World")
+	undo()
+	assert.Equal(t, "Hello", tls.Get())
+	assert.Equal(t, context.Background(), Context())
+}
+
+func TestImportContext_InheritPolicy(t *testing.T) {
+	count := 0
+	tls := NewInheritableThreadLocalWithOptions(InheritableThreadLocalOptions[int]{
+		Policy: InheritPolicyChildValue,
+		ChildValue: func(parent int) int {
+			count++
+			return parent + 1
+		},
+	})
+	tls2 := NewInheritableThreadLocalWithOptions(InheritableThreadLocalOptions[string]{Policy: InheritPolicySkip})
+	tls3 := NewInheritableThreadLocal[*personCloneable]()
+	defer tls.Close()
+	defer tls2.Close()
+	defer tls3.Close()
+	value := &personCloneable{Id: 1, Name: "Hello"}
+	task := GoWait(func(token CancelToken) {
+		tls.Set(1)
+		tls2.Set("skip")
+		tls3.Set(value)
+		undo := ImportContext(context.Background())
+		assert.Equal(t, 1, tls.Get())
+		assert.Equal(t, 0, count)
+		assert.True(t, tls2.IsSet())
+		assert.Equal(t, "skip", tls2.Get())
+		assert.Same(t, value, tls3.Get())
+		tls2.Remove()
+		undo()
+		assert.Equal(t, "skip", tls2.Get())
+	})
+	task.Get()
+}
@@ -1,5 +1,7 @@
 package routine
 
+import "fmt"
+
 // RuntimeError runtime error with stack info.
 type RuntimeError interface {
 	// Goid returns the goid of the coroutine that created the current error.
@@ -8,6 +10,9 @@ type RuntimeError interface {
 	// Gopc returns the pc of go statement that created the current error coroutine.
 	Gopc() uintptr
 
+	// Ancestors returns the ancestors of the coroutine that created the current error, the parent comes first.
+	Ancestors() []Ancestor
+
 	// Message returns the detail message string of this error.
 	Message() string
 
@@ -17,24 +22,63 @@ type RuntimeError interface {
 	// Cause returns the cause of this error or nil if the cause is nonexistent or unknown.
 	Cause() RuntimeError
 
-	// Error returns a short description of this error.
+	// Unwrap returns the inner RuntimeError or the original error which caused this error, so that errors.Is and errors.As work through the chain.
+	Unwrap() error
+
+	// Frames returns the stack frames of this error, the frames hidden when printing the error are marked as Hidden.
+	Frames() []Frame
+
+	// Error returns a short description of this error, it is rendered by the global StackFormatter.
 	Error() string
+
+	// Format implements fmt.Formatter, the %v and %s print the message, the %+v prints the full stack trace same as Error, the %q prints the quoted message.
+	Format(s fmt.State, verb rune)
+
+	// MarshalJSON implements json.Marshaler, the message, stack frames, created by frame and the cause chain are included.
+	MarshalJSON() ([]byte, error)
+}
+
+// Frame represents a stack frame of RuntimeError.
+type Frame struct {
+	// Function is the package path-qualified function name of this frame.
+	Function string `json:"function"`
+
+	// File is the file name of this frame.
+	File string `json:"file"`
+
+	// Line is the line number of this frame.
+	Line int `json:"line"`
+
+	// Package is the package path of the function.
+	Package string `json:"package"`
+
+	// Hidden is true if this frame is a runtime internal frame, which will not be printed.
+	Hidden bool `json:"hidden"`
+
+	offset uintptr
 }
 
 // NewRuntimeError create a new RuntimeError instance.
 func NewRuntimeError(cause any) RuntimeError {
-	goid, gopc, msg, stackTrace, innerErr := runtimeErrorNew(cause)
-	return &runtimeError{goid: goid, gopc: gopc, message: msg, stackTrace: stackTrace, cause: innerErr}
+	goid, gopc, msg, stackTrace, innerErr, originErr := runtimeErrorNew(cause)
+	return &runtimeError{goid: goid, gopc: gopc, ancestors: currentAncestors(), message: msg, stackTrace: stackTrace, cause: innerErr, err: originErr}
+}
+
+// NewRuntimeErrorWithOptions create a new RuntimeError instance, the stack trace is captured and filtered by the options instead of the global StackOptions.
+func NewRuntimeErrorWithOptions(cause any, options StackOptions) RuntimeError {
+	clone := options.clone()
+	goid, gopc, msg, stackTrace, innerErr, originErr := runtimeErrorNewWithOptions(cause, clone)
+	return &runtimeError{goid: goid, gopc: gopc, ancestors: currentAncestors(), message: msg, stackTrace: stackTrace, cause: innerErr, err: originErr, options: clone}
 }
 
 // NewRuntimeErrorWithMessage create a new RuntimeError instance.
 func NewRuntimeErrorWithMessage(message string) RuntimeError {
-	goid, gopc, msg, stackTrace, innerErr := runtimeErrorNewWithMessage(message)
-	return &runtimeError{goid: goid, gopc: gopc, message: msg, stackTrace: stackTrace, cause: innerErr}
+	goid, gopc, msg, stackTrace, innerErr, originErr := runtimeErrorNewWithMessage(message)
+	return &runtimeError{goid: goid, gopc: gopc, ancestors: currentAncestors(), message: msg, stackTrace: stackTrace, cause: innerErr, err: originErr}
 }
 
 // NewRuntimeErrorWithMessageCause create a new RuntimeError instance.
 func NewRuntimeErrorWithMessageCause(message string, cause any) RuntimeError {
-	goid, gopc, msg, stackTrace, innerErr := runtimeErrorNewWithMessageCause(message, cause)
-	return &runtimeError{goid: goid, gopc: gopc, message: msg, stackTrace: stackTrace, cause: innerErr}
+	goid, gopc, msg, stackTrace, innerErr, originErr := runtimeErrorNewWithMessageCause(message, cause)
+	return &runtimeError{goid: goid, gopc: gopc, ancestors: currentAncestors(), message: msg, stackTrace: stackTrace, cause: innerErr, err: originErr}
 }
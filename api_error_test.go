@@ -545,3 +545,34 @@ func BenchmarkRuntimeErrorWithMessageCause(b *testing.B) {
 		_ = NewRuntimeErrorWithMessageCause("", nil).Error()
 	}
 }
+
+func TestNewRuntimeErrorWithOptions(t *testing.T) {
+	err := newRuntimeErrorByHelper()
+	frames := err.Frames()
+	assert.Equal(t, "github.com/timandy/routine.TestNewRuntimeErrorWithOptions", frames[0].Function)
+	assert.Equal(t, "Hello", err.Message())
+	//
+	err2 := NewRuntimeErrorWithOptions(nil, StackOptions{MaxDepth: 2})
+	assert.Equal(t, 2, len(err2.StackTrace()))
+	//
+	err3 := NewRuntimeErrorWithOptions(nil, StackOptions{AllowPackages: []string{"testing"}})
+	frames3 := err3.Frames()
+	assert.True(t, frames3[0].Hidden)
+	assert.False(t, frames3[1].Hidden)
+	assert.False(t, strings.Contains(err3.Error(), "TestNewRuntimeErrorWithOptions"))
+	//
+	err4 := NewRuntimeErrorWithOptions(nil, StackOptions{})
+	err5 := NewRuntimeErrorWithOptions(nil, StackOptions{ShowRuntimeFrames: true})
+	assert.Equal(t, "runtime.goexit", err5.Frames()[len(err5.Frames())-1].Function)
+	assert.True(t, err4.Frames()[len(err4.Frames())-1].Hidden)
+	assert.False(t, err5.Frames()[len(err5.Frames())-1].Hidden)
+	assert.True(t, strings.Contains(err5.Error(), "runtime.goexit"))
+	//
+	cause := errors.New("error")
+	err6 := NewRuntimeErrorWithOptions(cause, StackOptions{})
+	assert.Same(t, cause, errors.Unwrap(err6))
+}
+
+func newRuntimeErrorByHelper() RuntimeError {
+	return NewRuntimeErrorWithOptions("Hello", StackOptions{Skip: 1})
+}
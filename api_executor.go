@@ -0,0 +1,97 @@
+package routine
+
+import "time"
+
+// ErrRejected is the cause of the RuntimeError when a task was rejected by an Executor, it can be checked by errors.Is.
+var ErrRejected error = taskError("Task was rejected.")
+
+// RejectPolicy decides how to handle the task when the queue of an Executor is full.
+type RejectPolicy int
+
+const (
+	// RejectPolicyBlock blocks the caller until the queue has free space or the executor is shutdown.
+	RejectPolicyBlock RejectPolicy = iota
+
+	// RejectPolicyCallerRuns runs the task in the caller goroutine.
+	RejectPolicyCallerRuns
+
+	// RejectPolicyFail fails the task with a RuntimeError caused by ErrRejected.
+	RejectPolicyFail
+)
+
+// Task provides a task which can be executed by an Executor, the FutureTask instances are all Tasks.
+type Task interface {
+	// Run execute the task, the method can be called repeatedly, but the task will only execute once.
+	Run()
+
+	// Cancel notifies the waiting coroutine that the task has canceled.
+	Cancel()
+
+	// Fail notifies the waiting coroutine that the task has terminated due to panic.
+	Fail(error any)
+}
+
+// Executor executes the submitted tasks by a group of goroutines.
+type Executor interface {
+	// Execute runs the task in a worker goroutine at some time in the future.
+	// The task created by WrapTask, WrapWaitTask, WrapWaitResultTask methods runs with the inheritableThreadLocals captured when it was created.
+	// If the executor is shutdown or the queue is full with RejectPolicyFail, the task will fail with a RuntimeError caused by ErrRejected.
+	// If the Run method of the task panics in a worker goroutine, the task will fail with a RuntimeError caused by the panic and the worker keeps running.
+	Execute(task Task)
+
+	// Submit create a new task and capture the inheritableThreadLocals from the current goroutine, then execute it in a worker goroutine.
+	// The panic will be trigger again when calling FutureTask.Get or FutureTask.GetWithTimeout method.
+	Submit(fun Runnable) FutureTask[any]
+
+	// Shutdown initiates an orderly shutdown, the queued tasks will be executed, but no new tasks will be accepted.
+	Shutdown()
+
+	// ShutdownNow shutdown the executor, cancels the running tasks and returns the queued tasks which never commenced execution.
+	// The returned tasks are left pending, so that they can be executed elsewhere or be canceled by the caller.
+	// The queued tasks taken by the workers during the shutdown are canceled instead of being executed.
+	ShutdownNow() []Task
+
+	// IsShutdown returns true if the executor has been shutdown.
+	IsShutdown() bool
+
+	// IsTerminated returns true if all tasks have completed following shutdown.
+	IsTerminated() bool
+
+	// AwaitTermination blocks until all tasks have completed after shutdown, or the timeout occurs, returns true if the executor terminated.
+	AwaitTermination(timeout time.Duration) bool
+}
+
+// NewFixedExecutor create and return a new Executor with a fixed number of worker goroutines and a bounded queue.
+// The caller will be blocked when the queue is full.
+func NewFixedExecutor(workers int, queueSize int) Executor {
+	return NewFixedExecutorWithPolicy(workers, queueSize, RejectPolicyBlock)
+}
+
+// NewFixedExecutorWithPolicy create and return a new Executor with a fixed number of worker goroutines and a bounded queue.
+// The policy decides how to handle the task when the queue is full.
+func NewFixedExecutorWithPolicy(workers int, queueSize int, policy RejectPolicy) Executor {
+	if workers <= 0 {
+		panic("workers must be greater than 0.")
+	}
+	if queueSize < 0 {
+		panic("queueSize can not be negative.")
+	}
+	if policy < RejectPolicyBlock || policy > RejectPolicyFail {
+		panic("policy is invalid.")
+	}
+	return newFixedExecutor(workers, queueSize, policy)
+}
+
+// SubmitCallable create a new task and capture the inheritableThreadLocals from the current goroutine, then execute it in a worker goroutine of the executor.
+// The return value can be got by FutureTask.Get or FutureTask.GetWithTimeout method.
+// The panic will be trigger again when calling FutureTask.Get or FutureTask.GetWithTimeout method.
+func SubmitCallable[TResult any](executor Executor, fun Callable[TResult]) FutureTask[TResult] {
+	if fun == nil {
+		panic("fun can not be nil.")
+	}
+	task := wrapWaitResultTask(func(token CancelToken) TResult {
+		return fun()
+	}, 1)
+	executor.Execute(task)
+	return task
+}
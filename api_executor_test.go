@@ -0,0 +1,243 @@
+package routine
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFixedExecutor(t *testing.T) {
+	executor := NewFixedExecutor(2, 1)
+	assert.NotNil(t, executor)
+	assert.False(t, executor.IsShutdown())
+	assert.False(t, executor.IsTerminated())
+	executor.Shutdown()
+	assert.True(t, executor.AwaitTermination(time.Second))
+	//
+	assert.Panics(t, func() {
+		NewFixedExecutor(0, 1)
+	})
+	assert.Panics(t, func() {
+		NewFixedExecutor(1, -1)
+	})
+	assert.Panics(t, func() {
+		NewFixedExecutorWithPolicy(1, 1, RejectPolicy(-1))
+	})
+	assert.Panics(t, func() {
+		NewFixedExecutorWithPolicy(1, 1, RejectPolicyFail+1)
+	})
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	tls := NewInheritableThreadLocal[string]()
+	tls.Set("Hello")
+	executor := NewFixedExecutor(1, 0)
+	defer executor.Shutdown()
+	task := WrapWaitResultTask(func(token CancelToken) string {
+		return tls.Get()
+	})
+	tls.Set("World")
+	executor.Execute(task)
+	assert.Equal(t, "Hello", task.Get())
+	//
+	assert.Panics(t, func() {
+		executor.Execute(nil)
+	})
+}
+
+func TestExecutor_Submit(t *testing.T) {
+	tls := NewInheritableThreadLocal[string]()
+	tls.Set("Hello")
+	executor := NewFixedExecutor(2, 4)
+	defer executor.Shutdown()
+	var value atomic.Value
+	task := executor.Submit(func() {
+		value.Store(tls.Get())
+	})
+	tls.Set("World")
+	assert.Nil(t, task.Get())
+	assert.Equal(t, "Hello", value.Load())
+	//
+	task2 := executor.Submit(func() {
+		panic("error")
+	})
+	_, err := task2.TryGet()
+	assert.Equal(t, "error", err.(RuntimeError).Message())
+	//
+	assert.Panics(t, func() {
+		executor.Submit(nil)
+	})
+}
+
+func TestSubmitCallable(t *testing.T) {
+	tls := NewInheritableThreadLocal[string]()
+	tls.Set("Hello")
+	executor := NewFixedExecutor(2, 4)
+	defer executor.Shutdown()
+	task := SubmitCallable(executor, func() string {
+		return tls.Get()
+	})
+	tls.Set("World")
+	assert.Equal(t, "Hello", task.Get())
+	//
+	assert.Panics(t, func() {
+		SubmitCallable[int](executor, nil)
+	})
+}
+
+func TestExecutor_RejectPolicyBlock(t *testing.T) {
+	executor := NewFixedExecutorWithPolicy(1, 1, RejectPolicyBlock)
+	release := make(chan struct{})
+	task := executor.Submit(func() {
+		<-release
+	})
+	task2 := executor.Submit(func() {})
+	submitted := make(chan struct{})
+	var task3 FutureTask[any]
+	go func() {
+		task3 = executor.Submit(func() {})
+		close(submitted)
+	}()
+	select {
+	case <-submitted:
+		assert.Fail(t, "should be blocked")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	<-submitted
+	assert.Nil(t, task.Get())
+	assert.Nil(t, task2.Get())
+	assert.Nil(t, task3.Get())
+	executor.Shutdown()
+	assert.True(t, executor.AwaitTermination(time.Second))
+}
+
+func TestExecutor_RejectPolicyBlock_Shutdown(t *testing.T) {
+	executor := NewFixedExecutorWithPolicy(1, 0, RejectPolicyBlock)
+	release := make(chan struct{})
+	task := executor.Submit(func() {
+		<-release
+	})
+	time.Sleep(10 * time.Millisecond)
+	submitted := make(chan FutureTask[any])
+	go func() {
+		submitted <- executor.Submit(func() {})
+	}()
+	time.Sleep(10 * time.Millisecond)
+	executor.Shutdown()
+	task2 := <-submitted
+	_, err := task2.TryGet()
+	assert.True(t, errors.Is(err, ErrRejected))
+	close(release)
+	assert.Nil(t, task.Get())
+	assert.True(t, executor.AwaitTermination(time.Second))
+}
+
+func TestExecutor_RejectPolicyCallerRuns(t *testing.T) {
+	executor := NewFixedExecutorWithPolicy(1, 1, RejectPolicyCallerRuns)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	task := executor.Submit(func() {
+		close(started)
+		<-release
+	})
+	<-started
+	task1 := executor.Submit(func() {})
+	goid := Goid()
+	var runGoid uint64
+	task2 := executor.Submit(func() {
+		runGoid = Goid()
+	})
+	assert.True(t, task2.IsDone())
+	assert.Equal(t, goid, runGoid)
+	close(release)
+	assert.Nil(t, task.Get())
+	assert.Nil(t, task1.Get())
+	executor.Shutdown()
+	assert.True(t, executor.AwaitTermination(time.Second))
+}
+
+func TestExecutor_RejectPolicyFail(t *testing.T) {
+	executor := NewFixedExecutorWithPolicy(1, 1, RejectPolicyFail)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	task := executor.Submit(func() {
+		close(started)
+		<-release
+	})
+	<-started
+	task1 := executor.Submit(func() {})
+	task2 := executor.Submit(func() {})
+	assert.True(t, task2.IsFailed())
+	_, err := task2.TryGet()
+	assert.True(t, errors.Is(err, ErrRejected))
+	assert.Equal(t, "Task was rejected.", err.(RuntimeError).Message())
+	close(release)
+	assert.Nil(t, task.Get())
+	assert.Nil(t, task1.Get())
+	executor.Shutdown()
+	assert.True(t, executor.AwaitTermination(time.Second))
+}
+
+func TestExecutor_Shutdown(t *testing.T) {
+	executor := NewFixedExecutor(1, 8)
+	var count int32
+	tasks := make([]FutureTask[any], 0, 8)
+	for i := 0; i < 8; i++ {
+		tasks = append(tasks, executor.Submit(func() {
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&count, 1)
+		}))
+	}
+	executor.Shutdown()
+	executor.Shutdown()
+	assert.True(t, executor.IsShutdown())
+	assert.True(t, executor.AwaitTermination(time.Second))
+	assert.True(t, executor.IsTerminated())
+	assert.Equal(t, int32(8), atomic.LoadInt32(&count))
+	for _, task := range tasks {
+		assert.True(t, task.IsDone())
+	}
+	//
+	task := executor.Submit(func() {})
+	_, err := task.TryGet()
+	assert.True(t, errors.Is(err, ErrRejected))
+}
+
+func TestExecutor_ShutdownNow(t *testing.T) {
+	executor := NewFixedExecutor(1, 8)
+	started := make(chan struct{})
+	task := executor.Submit(func() {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+	})
+	<-started
+	for i := 0; i < 3; i++ {
+		executor.Submit(func() {})
+	}
+	pending := executor.ShutdownNow()
+	assert.Len(t, pending, 3)
+	for _, p := range pending {
+		assert.False(t, p.(FutureTask[any]).IsDone())
+	}
+	assert.True(t, task.IsCanceled())
+	assert.True(t, executor.AwaitTermination(time.Second))
+	assert.Len(t, executor.ShutdownNow(), 0)
+}
+
+func TestExecutor_AwaitTermination(t *testing.T) {
+	executor := NewFixedExecutor(1, 0)
+	release := make(chan struct{})
+	executor.Submit(func() {
+		<-release
+	})
+	assert.False(t, executor.AwaitTermination(10*time.Millisecond))
+	executor.Shutdown()
+	assert.False(t, executor.AwaitTermination(10*time.Millisecond))
+	assert.False(t, executor.IsTerminated())
+	close(release)
+	assert.True(t, executor.AwaitTermination(time.Second))
+}
@@ -0,0 +1,149 @@
+package routine
+
+import "sync/atomic"
+
+// Then create a new task which will be completed with the return value of fun when the task completed normally.
+// If the task is completed exceptionally or via cancellation, the returned task will fail with the same RuntimeError.
+// The fun will be executed in the goroutine which completes the task, with the inheritableThreadLocals captured from the current goroutine.
+func Then[T any, R any](task FutureTask[T], fun func(result T) R) FutureTask[R] {
+	checkTask(task)
+	if fun == nil {
+		panic("fun can not be nil.")
+	}
+	next := NewFutureTask(func(next FutureTask[R]) R {
+		return fun(task.Get())
+	})
+	task.OnComplete(func(result T, err RuntimeError) {
+		next.Run()
+	})
+	return next
+}
+
+// ThenAsync create a new task which will be completed with the result of the task returned by fun when the task completed normally.
+// If any of the tasks is completed exceptionally or via cancellation, the returned task will fail with the same RuntimeError.
+// If fun returns nil, the returned task will fail with a RuntimeError.
+// The fun will be executed in the goroutine which completes the task, with the inheritableThreadLocals captured from the current goroutine.
+// The Run method of the returned task does not execute anything, it blocks until the returned task is completed, so do not submit it to an Executor.
+func ThenAsync[T any, R any](task FutureTask[T], fun func(result T) FutureTask[R]) FutureTask[R] {
+	stage := Then(task, fun)
+	next := newPromise[R]()
+	stage.OnComplete(func(inner FutureTask[R], err RuntimeError) {
+		if err != nil {
+			next.Fail(err)
+			return
+		}
+		if inner == nil {
+			next.Fail(NewRuntimeErrorWithMessage("task returned by fun can not be nil."))
+			return
+		}
+		inner.OnComplete(func(result R, err RuntimeError) {
+			completeWith(next, result, err)
+		})
+	})
+	return next
+}
+
+// Recover create a new task which will be completed with the result of the task when the task completed normally,
+// or with the return value of fun when the task completed exceptionally or via cancellation.
+// The fun will be executed in the goroutine which completes the task, with the inheritableThreadLocals captured from the current goroutine.
+func Recover[T any](task FutureTask[T], fun func(err RuntimeError) T) FutureTask[T] {
+	checkTask(task)
+	if fun == nil {
+		panic("fun can not be nil.")
+	}
+	next := NewFutureTask(func(next FutureTask[T]) T {
+		result, err := task.TryGet()
+		if err != nil {
+			return fun(err.(RuntimeError))
+		}
+		return result
+	})
+	task.OnComplete(func(result T, err RuntimeError) {
+		next.Run()
+	})
+	return next
+}
+
+// Zip create a new task which will be completed with the return value of fun when both of the tasks completed normally.
+// If any of the tasks is completed exceptionally or via cancellation, the returned task will fail with the same RuntimeError immediately, without waiting for the other task.
+// The fun will be executed in the goroutine which completes the last task, with the inheritableThreadLocals captured from the current goroutine.
+func Zip[T1 any, T2 any, R any](task1 FutureTask[T1], task2 FutureTask[T2], fun func(result1 T1, result2 T2) R) FutureTask[R] {
+	checkTask(task1)
+	checkTask(task2)
+	if fun == nil {
+		panic("fun can not be nil.")
+	}
+	next := NewFutureTask(func(next FutureTask[R]) R {
+		return fun(task1.Get(), task2.Get())
+	})
+	remaining := int32(2)
+	task1.OnComplete(func(result T1, err RuntimeError) {
+		if err != nil {
+			next.Fail(err)
+			return
+		}
+		if atomic.AddInt32(&remaining, -1) == 0 {
+			next.Run()
+		}
+	})
+	task2.OnComplete(func(result T2, err RuntimeError) {
+		if err != nil {
+			next.Fail(err)
+			return
+		}
+		if atomic.AddInt32(&remaining, -1) == 0 {
+			next.Run()
+		}
+	})
+	return next
+}
+
+// AllOf create a new task which will be completed with the results of all the tasks in order when all of them completed normally.
+// If any of the tasks is completed exceptionally or via cancellation, the returned task will fail with the same RuntimeError.
+func AllOf[T any](tasks ...FutureTask[T]) FutureTask[[]T] {
+	for _, task := range tasks {
+		checkTask(task)
+	}
+	next := NewFutureTask(func(next FutureTask[[]T]) []T {
+		results := make([]T, len(tasks))
+		for i, task := range tasks {
+			results[i] = task.Get()
+		}
+		return results
+	})
+	if len(tasks) == 0 {
+		next.Run()
+		return next
+	}
+	remaining := int32(len(tasks))
+	for _, task := range tasks {
+		task.OnComplete(func(result T, err RuntimeError) {
+			if err != nil {
+				next.Fail(err)
+				return
+			}
+			if atomic.AddInt32(&remaining, -1) == 0 {
+				next.Run()
+			}
+		})
+	}
+	return next
+}
+
+// AnyOf create a new task which will be completed with the same result or RuntimeError as the first completed task.
+// The Run method of the returned task does not execute anything, it blocks until the returned task is completed, so do not submit it to an Executor.
+func AnyOf[T any](tasks ...FutureTask[T]) FutureTask[T] {
+	if len(tasks) == 0 {
+		panic("tasks can not be empty.")
+	}
+	for _, task := range tasks {
+		checkTask(task)
+	}
+	next := newPromise[T]()
+	for _, task := range tasks {
+		task.OnComplete(func(result T, err RuntimeError) {
+			completeWith(next, result, err)
+		})
+	}
+	return next
+}
@@ -0,0 +1,178 @@
+package routine
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThen(t *testing.T) {
+	tls := NewInheritableThreadLocal[string]()
+	tls.Set("Hello")
+	task := GoWaitResult(func(token CancelToken) int {
+		return 1
+	})
+	task2 := Then(task, func(result int) string {
+		assert.Equal(t, "Hello", tls.Get())
+		return tls.Get() + string(rune('0'+result))
+	})
+	assert.Equal(t, "Hello1", task2.Get())
+	//
+	assert.Panics(t, func() {
+		Then[int, int](nil, func(result int) int { return result })
+	})
+	assert.Panics(t, func() {
+		Then[int, int](task, nil)
+	})
+}
+
+func TestThen_Fail(t *testing.T) {
+	task := GoWaitResult(func(token CancelToken) int {
+		panic("error")
+	})
+	run := false
+	task2 := Then(task, func(result int) int {
+		run = true
+		return result
+	})
+	_, err := task2.TryGet()
+	_, err2 := task.TryGet()
+	assert.Same(t, err2, err)
+	assert.False(t, run)
+	assert.True(t, task2.IsFailed())
+	//
+	task3 := NewFutureTask[int](func(task FutureTask[int]) int { return 1 })
+	task3.Cancel()
+	_, err3 := Then(task3, func(result int) int { return result }).TryGet()
+	assert.True(t, errors.Is(err3, ErrCanceled))
+}
+
+func TestThenAsync(t *testing.T) {
+	tls := NewInheritableThreadLocal[string]()
+	tls.Set("Hello")
+	task := GoWaitResult(func(token CancelToken) int {
+		return 1
+	})
+	task2 := ThenAsync(task, func(result int) FutureTask[string] {
+		return GoWaitResult(func(token CancelToken) string {
+			return tls.Get()
+		})
+	})
+	assert.Equal(t, "Hello", task2.Get())
+	//
+	task3 := ThenAsync(task, func(result int) FutureTask[string] {
+		return GoWaitResult(func(token CancelToken) string {
+			panic("error")
+		})
+	})
+	_, err := task3.TryGet()
+	assert.Equal(t, "error", err.(RuntimeError).Message())
+}
+
+func TestThenAsync_NilTask(t *testing.T) {
+	task := GoWaitResult(func(token CancelToken) int {
+		return 1
+	})
+	task2 := ThenAsync(task, func(result int) FutureTask[string] {
+		return nil
+	})
+	_, err := task2.TryGet()
+	assert.NotNil(t, err)
+	assert.Equal(t, "task returned by fun can not be nil.", err.(RuntimeError).Message())
+	assert.True(t, task2.IsFailed())
+}
+
+func TestRecover(t *testing.T) {
+	tls := NewInheritableThreadLocal[string]()
+	tls.Set("Hello")
+	task := GoWaitResult(func(token CancelToken) string {
+		panic("error")
+	})
+	task2 := Recover(task, func(err RuntimeError) string {
+		return tls.Get() + " " + err.Message()
+	})
+	assert.Equal(t, "Hello error", task2.Get())
+	//
+	task3 := GoWaitResult(func(token CancelToken) string {
+		return "World"
+	})
+	task4 := Recover(task3, func(err RuntimeError) string {
+		return err.Message()
+	})
+	assert.Equal(t, "World", task4.Get())
+}
+
+func TestZip(t *testing.T) {
+	task := GoWaitResult(func(token CancelToken) int {
+		return 1
+	})
+	task2 := GoWaitResult(func(token CancelToken) string {
+		return "Hello"
+	})
+	task3 := Zip(task, task2, func(result1 int, result2 string) string {
+		return result2 + string(rune('0'+result1))
+	})
+	assert.Equal(t, "Hello1", task3.Get())
+	//
+	task4 := GoWaitResult(func(token CancelToken) string {
+		panic("error")
+	})
+	_, err := Zip(task, task4, func(result1 int, result2 string) string {
+		return result2
+	}).TryGet()
+	assert.Equal(t, "error", err.(RuntimeError).Message())
+	//
+	pending := NewFutureTask(func(task FutureTask[string]) string {
+		return "never"
+	})
+	_, err = Zip(task4, pending, func(result1 string, result2 string) string {
+		return result1 + result2
+	}).TryGetWithTimeout(time.Second)
+	assert.Equal(t, "error", err.(RuntimeError).Message())
+	assert.False(t, pending.IsDone())
+}
+
+func TestAllOf(t *testing.T) {
+	tasks := make([]FutureTask[int], 10)
+	for i := 0; i < len(tasks); i++ {
+		value := i
+		tasks[i] = GoWaitResult(func(token CancelToken) int {
+			time.Sleep(time.Duration(10-value) * time.Millisecond)
+			return value
+		})
+	}
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, AllOf(tasks...).Get())
+	assert.Equal(t, []int{}, AllOf[int]().Get())
+	//
+	failed := GoWaitResult(func(token CancelToken) int {
+		panic("error")
+	})
+	_, err := AllOf(tasks[0], failed).TryGet()
+	assert.Equal(t, "error", err.(RuntimeError).Message())
+}
+
+func TestAnyOf(t *testing.T) {
+	task := NewFutureTask[int](func(task FutureTask[int]) int { return 1 })
+	task2 := GoWaitResult(func(token CancelToken) int {
+		return 2
+	})
+	assert.Equal(t, 2, AnyOf(task, task2).Get())
+	task.Complete(1)
+	//
+	task3 := NewFutureTask[int](func(task FutureTask[int]) int { return 1 })
+	task4 := GoWaitResult(func(token CancelToken) int {
+		panic("error")
+	})
+	_, err := AnyOf(task3, task4).TryGet()
+	assert.Equal(t, "error", err.(RuntimeError).Message())
+	task3.Complete(1)
+	//
+	assert.Panics(t, func() {
+		AnyOf[int]()
+	})
+	assert.Panics(t, func() {
+		AnyOf[int](nil)
+	})
+}
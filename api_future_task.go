@@ -1,10 +1,25 @@
 package routine
 
-import "time"
+import (
+	"context"
+	"time"
+)
+
+var (
+	// ErrCanceled is the cause of the RuntimeError when a task was canceled, it can be checked by errors.Is.
+	ErrCanceled error = taskError("Task was canceled.")
+
+	// ErrTimeout is the cause of the RuntimeError when a task execution timeout, it can be checked by errors.Is.
+	ErrTimeout error = taskError("Task execution timeout.")
+)
 
 // FutureCallable provides a future function that returns a value of type TResult.
 type FutureCallable[TResult any] func(task FutureTask[TResult]) TResult
 
+// FutureCallback provides a function to be invoked when the task is completed.
+// The err is nil if the task completed normally.
+type FutureCallback[TResult any] func(result TResult, err RuntimeError)
+
 // CancelToken propagates notification that operations should be canceled.
 type CancelToken interface {
 	// IsCanceled returns true if task was canceled.
@@ -12,6 +27,21 @@ type CancelToken interface {
 
 	// Cancel notifies the waiting coroutine that the task has canceled and returns stack information.
 	Cancel()
+
+	// Done returns a channel that is closed when the task is canceled or completed in any fashion.
+	Done() <-chan struct{}
+
+	// Err returns nil if the task was not canceled, otherwise a RuntimeError caused by ErrCanceled or ErrTimeout.
+	Err() error
+
+	// OnCancel registers a function which will be invoked when the task is canceled, it will never be invoked if the task is completed normally or exceptionally.
+	// The fun will be invoked in the goroutine which cancels the task, or in the current goroutine if the task is already canceled.
+	OnCancel(fun Runnable)
+
+	// Context returns a context.Context derived from the context stored in the goroutine which calls it first, which is done when the task is canceled or completed in any fashion.
+	// The same context.Context is returned by the later calls, it stays registered to the parent until the task is done.
+	// The ctx.Err() is the error of the parent if the parent is done first, context.DeadlineExceeded if the task execution timeout, otherwise context.Canceled.
+	Context() context.Context
 }
 
 // FutureTask provide a way to wait for the sub-coroutine to finish executing, get the return value of the sub-coroutine, and catch the sub-coroutine panic.
@@ -45,6 +75,45 @@ type FutureTask[TResult any] interface {
 	// If the deadline is reached, a panic with timeout error will be raised.
 	GetWithTimeout(timeout time.Duration) TResult
 
+	// TryGet return the execution result of the sub-coroutine, if there is no result, return nil.
+	// If task is canceled, a RuntimeError caused by ErrCanceled will be returned.
+	// If panic is raised during the execution of the sub-coroutine, the RuntimeError will be returned.
+	TryGet() (TResult, error)
+
+	// TryGetWithTimeout return the execution result of the sub-coroutine, if there is no result, return nil.
+	// If task is canceled, a RuntimeError caused by ErrCanceled will be returned.
+	// If panic is raised during the execution of the sub-coroutine, the RuntimeError will be returned.
+	// If the deadline is reached, the task will be canceled and a RuntimeError caused by ErrTimeout will be returned.
+	TryGetWithTimeout(timeout time.Duration) (TResult, error)
+
+	// Done returns a channel that is closed when the task is completed in any fashion: normally, exceptionally or via cancellation.
+	Done() <-chan struct{}
+
+	// Err returns nil if the task was not canceled, otherwise a RuntimeError caused by ErrCanceled or ErrTimeout.
+	Err() error
+
+	// OnCancel registers a function which will be invoked when the task is canceled, it will never be invoked if the task is completed normally or exceptionally.
+	// The fun will be invoked in the goroutine which cancels the task, or in the current goroutine if the task is already canceled.
+	OnCancel(fun Runnable)
+
+	// Context returns a context.Context derived from the context stored in the goroutine which calls it first, which is done when the task is canceled or completed in any fashion.
+	// The same context.Context is returned by the later calls, it stays registered to the parent until the task is done.
+	// The ctx.Err() is the error of the parent if the parent is done first, context.DeadlineExceeded if the task execution timeout, otherwise context.Canceled.
+	Context() context.Context
+
+	// GetContext return the execution result of the sub-coroutine, if there is no result, return nil.
+	// If task is canceled or panic is raised during the execution of the sub-coroutine, the RuntimeError will be returned.
+	// If the ctx is done before the task is done, ctx.Err() will be returned and the task will not be affected.
+	GetContext(ctx context.Context) (TResult, error)
+
+	// CancelWithContext cancels the task when the ctx is done before the task is done.
+	CancelWithContext(ctx context.Context)
+
+	// OnComplete registers a callback which will be invoked exactly once when the task is completed in any fashion.
+	// The callback will be invoked in the goroutine which completes the task, or in the current goroutine if the task is already completed.
+	// The callback runs with the inheritableThreadLocals captured from the current goroutine, a panic raised by the callback will be caught and handled by the UncaughtPanicHandler.
+	OnComplete(callback FutureCallback[TResult])
+
 	// Run execute the task, the method can be called repeatedly, but the task will only execute once.
 	Run()
 }
@@ -54,7 +123,5 @@ func NewFutureTask[TResult any](callable FutureCallable[TResult]) FutureTask[TRe
 	if callable == nil {
 		panic("callable can not be nil.")
 	}
-	task := &futureTask[TResult]{callable: callable}
-	task.await.Add(1)
-	return task
+	return &futureTask[TResult]{callable: callable, done: make(chan struct{})}
 }
@@ -1,6 +1,8 @@
 package routine
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -35,3 +37,27 @@ func TestNewFutureTask(t *testing.T) {
 	assert.Same(t, p, task)
 	assert.True(t, ok)
 }
+
+func TestErrCanceled(t *testing.T) {
+	assert.Equal(t, "Task was canceled.", ErrCanceled.Error())
+	assert.Equal(t, "Task execution timeout.", ErrTimeout.Error())
+	assert.NotEqual(t, ErrCanceled, ErrTimeout)
+}
+
+func TestCancelToken_Context(t *testing.T) {
+	WithContext(context.WithValue(context.Background(), contextKey{}, "Hello"))
+	defer contextThreadLocal.Remove()
+	started := make(chan struct{})
+	task := GoWaitResult(func(token CancelToken) error {
+		ctx := token.Context()
+		assert.Equal(t, "Hello", ctx.Value(contextKey{}))
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	<-started
+	task.Cancel()
+	<-task.Done()
+	assert.True(t, task.IsCanceled())
+	assert.True(t, errors.Is(task.Err(), ErrCanceled))
+}
@@ -4,3 +4,30 @@ package routine
 func Goid() uint64 {
 	return getg().goid()
 }
+
+// ParentGoid returns the goid of the goroutine which started the current goroutine.
+// For the goroutines started by Go, GoWait, GoWaitResult methods or the tasks created by WrapTask, WrapWaitTask, WrapWaitResultTask methods, it is the goid of the goroutine which started or created them.
+// Otherwise, it is read from the runtime since go1.21, and it is 0 before go1.21 or for the main goroutine.
+func ParentGoid() uint64 {
+	if ancestors := currentAncestors(); len(ancestors) > 0 {
+		return ancestors[0].Goid
+	}
+	return getg().parentGoid()
+}
+
+// ParentGoids returns the goids of the ancestors of the current goroutine, the parent comes first.
+// The chain is recorded by Go, GoWait, GoWaitResult, WrapTask, WrapWaitTask, WrapWaitResultTask methods and at most 16 levels are kept.
+// If the chain is not recorded, only the parent read from the runtime is returned, or nil if the parent is not available.
+func ParentGoids() []uint64 {
+	if ancestors := currentAncestors(); len(ancestors) > 0 {
+		goids := make([]uint64, len(ancestors))
+		for i, ancestor := range ancestors {
+			goids[i] = ancestor.Goid
+		}
+		return goids
+	}
+	if parentGoid := getg().parentGoid(); parentGoid != 0 {
+		return []uint64{parentGoid}
+	}
+	return nil
+}
@@ -11,6 +11,47 @@ func TestGoid(t *testing.T) {
 	assert.Equal(t, Goid(), Goid())
 }
 
+func TestParentGoid(t *testing.T) {
+	goid := Goid()
+	task := GoWait(func(token CancelToken) {
+		assert.Equal(t, goid, ParentGoid())
+		goid2 := Goid()
+		task2 := GoWaitResult(func(token CancelToken) uint64 {
+			assert.Equal(t, []uint64{goid2, goid}, ParentGoids())
+			return ParentGoid()
+		})
+		assert.Equal(t, goid2, task2.Get())
+		//
+		wrapped := WrapTask(func() {
+			assert.Equal(t, goid2, ParentGoid())
+			assert.Equal(t, []uint64{goid2, goid}, ParentGoids())
+		})
+		done := make(chan struct{})
+		go func() {
+			wrapped.Run()
+			close(done)
+		}()
+		<-done
+	})
+	task.Get()
+}
+
+func TestParentGoid_Runtime(t *testing.T) {
+	goid := Goid()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if offsetParentGoid == 0 {
+			assert.Equal(t, uint64(0), ParentGoid())
+			assert.Nil(t, ParentGoids())
+			return
+		}
+		assert.Equal(t, goid, ParentGoid())
+		assert.Equal(t, []uint64{goid}, ParentGoids())
+	}()
+	<-done
+}
+
 //===
 
 // BenchmarkGoid-8                                 331324310                3.589 ns/op           0 B/op          0 allocs/op
@@ -0,0 +1,81 @@
+package routine
+
+import "runtime"
+
+// GoroutineStatus is the status of a goroutine, the same as the atomicstatus of the runtime.
+type GoroutineStatus uint32
+
+const (
+	GoroutineStatusIdle      GoroutineStatus = 0      // just allocated and not yet initialized
+	GoroutineStatusRunnable  GoroutineStatus = 1      // on a run queue
+	GoroutineStatusRunning   GoroutineStatus = 2      // executing user code
+	GoroutineStatusSyscall   GoroutineStatus = 3      // executing a system call
+	GoroutineStatusWaiting   GoroutineStatus = 4      // blocked in the runtime
+	GoroutineStatusDead      GoroutineStatus = 6      // unused, just exited or on a free list
+	GoroutineStatusCopystack GoroutineStatus = 8      // the stack is being moved
+	GoroutineStatusPreempted GoroutineStatus = 9      // stopped itself for a preemption
+	GoroutineStatusScan      GoroutineStatus = 0x1000 // combined with other status, the stack is being scanned by the GC
+)
+
+// String returns the name of the status, e.g. "running", "scan waiting".
+func (s GoroutineStatus) String() string {
+	return goroutineStatusString(s)
+}
+
+// GoroutineInfo is a read-only view of a goroutine, it is a copy of the state of the goroutine when it was created.
+type GoroutineInfo struct {
+	// Goid is the goid of the goroutine.
+	Goid uint64
+
+	// ParentGoid is the goid of the goroutine which created this goroutine, it is 0 before go1.21 or for the main goroutine.
+	ParentGoid uint64
+
+	// Status is the status of the goroutine.
+	Status GoroutineStatus
+
+	// WaitReason is the reason why the goroutine is waiting, e.g. "chan receive", it is empty unless the status is GoroutineStatusWaiting.
+	// The values are defined by the runtime and vary between go versions.
+	WaitReason string
+
+	// StartPc is the pc of the function which the goroutine started with, it is 0 for the goroutines other than the current one.
+	StartPc uintptr
+
+	// CreatedPc is the pc of the go statement which created the goroutine, it is 0 for the goroutines other than the current one.
+	CreatedPc uintptr
+
+	startFrame   Frame
+	createdFrame Frame
+}
+
+// StartFrame resolves the function which the goroutine started with.
+// The frame of the goroutines other than the current one is the outermost frame of the stack, it is empty if the stack is unavailable.
+func (info GoroutineInfo) StartFrame() Frame {
+	if info.StartPc == 0 {
+		return info.startFrame
+	}
+	// the startpc is the entry of the function, not a return address
+	frame, _ := runtime.CallersFrames([]uintptr{info.StartPc + 1}).Next()
+	return newFrame(frame, false)
+}
+
+// CreatedFrame resolves the go statement which created the goroutine.
+// The frame of the goroutines other than the current one is resolved from the traceback, it is empty for the main goroutine.
+func (info GoroutineInfo) CreatedFrame() Frame {
+	if info.CreatedPc == 0 {
+		return info.createdFrame
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{info.CreatedPc}).Next()
+	return newFrame(frame, false)
+}
+
+// Current returns the GoroutineInfo of the current goroutine.
+func Current() GoroutineInfo {
+	return newGoroutineInfo(getg())
+}
+
+// Goroutines returns the GoroutineInfo of all the user goroutines, the current goroutine comes first.
+// The goroutines other than the current one are resolved from the traceback of runtime.Stack, it stops the world while collecting.
+// The status of them is read from the traceback too, so the GoroutineStatusScan is never reported.
+func Goroutines() []GoroutineInfo {
+	return goroutines()
+}
@@ -0,0 +1,88 @@
+package routine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoroutineStatus_String(t *testing.T) {
+	assert.Equal(t, "idle", GoroutineStatusIdle.String())
+	assert.Equal(t, "running", GoroutineStatusRunning.String())
+	assert.Equal(t, "waiting", GoroutineStatusWaiting.String())
+	assert.Equal(t, "scan waiting", (GoroutineStatusScan | GoroutineStatusWaiting).String())
+	assert.Equal(t, "GoroutineStatus(5)", GoroutineStatus(5).String())
+	assert.Equal(t, "scan GoroutineStatus(11)", (GoroutineStatusScan | 11).String())
+}
+
+func TestCurrent(t *testing.T) {
+	parentGoid := Goid()
+	done := make(chan GoroutineInfo)
+	line := currentLine() + 1
+	go func() {
+		done <- Current()
+	}()
+	info := <-done
+	assert.NotEqual(t, parentGoid, info.Goid)
+	if offsetParentGoid == 0 {
+		assert.Equal(t, uint64(0), info.ParentGoid)
+	} else {
+		assert.Equal(t, parentGoid, info.ParentGoid)
+	}
+	assert.Equal(t, GoroutineStatusRunning, info.Status)
+	assert.Greater(t, int64(info.StartPc), int64(0))
+	assert.Greater(t, int64(info.CreatedPc), int64(0))
+	//
+	startFrame := info.StartFrame()
+	assert.Equal(t, "github.com/timandy/routine.TestCurrent.func1", startFrame.Function)
+	assert.Equal(t, "github.com/timandy/routine", startFrame.Package)
+	createdFrame := info.CreatedFrame()
+	assert.Equal(t, "github.com/timandy/routine.TestCurrent", createdFrame.Function)
+	assert.Equal(t, line, createdFrame.Line)
+}
+
+func TestCurrent_Goid(t *testing.T) {
+	runTest(t, func() {
+		info := Current()
+		assert.Equal(t, Goid(), info.Goid)
+		assert.Equal(t, GoroutineStatusRunning, info.Status)
+	})
+}
+
+func TestGoroutines(t *testing.T) {
+	parentGoid := Goid()
+	started := make(chan uint64)
+	stop := make(chan struct{})
+	line := currentLine() + 1
+	go func() {
+		started <- Goid()
+		<-stop
+	}()
+	goid := <-started
+	defer close(stop)
+	//
+	infos := Goroutines()
+	assert.Equal(t, Current().Goid, infos[0].Goid)
+	assert.Equal(t, GoroutineStatusRunning, infos[0].Status)
+	var info *GoroutineInfo
+	for i := range infos {
+		if infos[i].Goid == goid {
+			info = &infos[i]
+		}
+	}
+	assert.NotNil(t, info)
+	if offsetParentGoid == 0 {
+		assert.Equal(t, uint64(0), info.ParentGoid)
+	} else {
+		assert.Equal(t, parentGoid, info.ParentGoid)
+	}
+	assert.Equal(t, GoroutineStatusWaiting, info.Status)
+	assert.Equal(t, "chan receive", info.WaitReason)
+	assert.Equal(t, uintptr(0), info.StartPc)
+	assert.Equal(t, uintptr(0), info.CreatedPc)
+	assert.Equal(t, "github.com/timandy/routine.TestGoroutines.func1", info.StartFrame().Function)
+	createdFrame := info.CreatedFrame()
+	assert.Equal(t, "github.com/timandy/routine.TestGoroutines", createdFrame.Function)
+	assert.Equal(t, "github.com/timandy/routine", createdFrame.Package)
+	assert.Equal(t, line, createdFrame.Line)
+}
@@ -0,0 +1,41 @@
+package routine
+
+// InheritPolicy decides how the value of an inheritable ThreadLocal is inherited by the sub goroutines, the FutureTasks and the Snapshots.
+type InheritPolicy int
+
+const (
+	// InheritPolicyClone inherits a copy created by the method Cloneable.Clone if the value implements Cloneable, otherwise shares the value.
+	// It is the default policy.
+	InheritPolicyClone InheritPolicy = iota
+
+	// InheritPolicyShare inherits the value itself even if it implements Cloneable.
+	InheritPolicyShare
+
+	// InheritPolicyChildValue inherits the return value of the method InheritableThreadLocalOptions.ChildValue.
+	InheritPolicyChildValue
+
+	// InheritPolicySkip does not inherit the value, the ThreadLocal will be unset in the sub goroutines.
+	InheritPolicySkip
+)
+
+// InheritableThreadLocalOptions configures the inheritable ThreadLocal created by NewInheritableThreadLocalWithOptions.
+type InheritableThreadLocalOptions[T any] struct {
+	// Supplier provides the initial value, the initial value is the default value of type T if it is nil.
+	Supplier Supplier[T]
+
+	// Policy decides how the value is inherited.
+	Policy InheritPolicy
+
+	// ChildValue computes the value of the child from the value of the parent, it is required by InheritPolicyChildValue.
+	// It is called in the parent goroutine when the inheritableThreadLocals are captured.
+	ChildValue func(parent T) T
+}
+
+// NewInheritableThreadLocalWithOptions create and return a new ThreadLocal instance.
+// The value is inherited to sub goroutines or captured to FutureTask by the policy of the options, instead of by the type of the value.
+func NewInheritableThreadLocalWithOptions[T any](options InheritableThreadLocalOptions[T]) ThreadLocal[T] {
+	inherit := newInheritFunc(options)
+	index, version := acquireInheritableThreadLocalIndex()
+	storeInheritFunc(index, version, inherit)
+	return &inheritableThreadLocal[T]{index: index, version: version, supplier: options.Supplier}
+}
@@ -0,0 +1,141 @@
+package routine
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewInheritableThreadLocalWithOptions_Clone(t *testing.T) {
+	tls := NewInheritableThreadLocalWithOptions(InheritableThreadLocalOptions[*personCloneable]{})
+	defer tls.Close()
+	value := &personCloneable{Id: 1, Name: "Tim"}
+	task := GoWait(func(token CancelToken) {
+		tls.Set(value)
+		task2 := GoWait(func(token CancelToken) {
+			assert.NotSame(t, value, tls.Get())
+			assert.Equal(t, value, tls.Get())
+		})
+		task2.Get()
+	})
+	task.Get()
+}
+
+func TestNewInheritableThreadLocalWithOptions_Share(t *testing.T) {
+	tls := NewInheritableThreadLocalWithOptions(InheritableThreadLocalOptions[*personCloneable]{Policy: InheritPolicyShare})
+	defer tls.Close()
+	value := &personCloneable{Id: 1, Name: "Tim"}
+	task := GoWait(func(token CancelToken) {
+		tls.Set(value)
+		task2 := GoWait(func(token CancelToken) {
+			assert.Same(t, value, tls.Get())
+		})
+		task2.Get()
+	})
+	task.Get()
+}
+
+func TestNewInheritableThreadLocalWithOptions_ChildValue(t *testing.T) {
+	tls := NewInheritableThreadLocalWithOptions(InheritableThreadLocalOptions[int]{
+		Supplier: func() int {
+			return 1
+		},
+		Policy: InheritPolicyChildValue,
+		ChildValue: func(parent int) int {
+			return parent + 1
+		},
+	})
+	defer tls.Close()
+	task := GoWait(func(token CancelToken) {
+		assert.Equal(t, 1, tls.Get())
+		task2 := GoWait(func(token CancelToken) {
+			assert.Equal(t, 2, tls.Get())
+			wrapped := WrapTask(func() {
+				assert.Equal(t, 3, tls.Get())
+			})
+			wrapped.Run()
+		})
+		task2.Get()
+	})
+	task.Get()
+	//
+	assert.PanicsWithValue(t, "childValue can not be nil.", func() {
+		NewInheritableThreadLocalWithOptions(InheritableThreadLocalOptions[int]{Policy: InheritPolicyChildValue})
+	})
+}
+
+func TestNewInheritableThreadLocalWithOptions_ChildValue_Snapshot(t *testing.T) {
+	count := 0
+	tls := NewInheritableThreadLocalWithOptions(InheritableThreadLocalOptions[int]{
+		Policy: InheritPolicyChildValue,
+		ChildValue: func(parent int) int {
+			count++
+			return parent + 1
+		},
+	})
+	defer tls.Close()
+	tls.Set(1)
+	snapshot := Capture()
+	assert.Equal(t, 1, count)
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		snapshot.Run(func() {
+			assert.Equal(t, 2, tls.Get())
+		})
+	}()
+	wg.Wait()
+	assert.Equal(t, 1, count)
+	//
+	snapshot.Run(func() {
+		assert.Equal(t, 2, tls.Get())
+	})
+	assert.Equal(t, 1, count)
+	assert.Equal(t, 1, tls.Get())
+}
+
+func TestNewInheritableThreadLocalWithOptions_Skip(t *testing.T) {
+	tls := NewInheritableThreadLocalWithOptions(InheritableThreadLocalOptions[string]{Policy: InheritPolicySkip})
+	defer tls.Close()
+	task := GoWait(func(token CancelToken) {
+		tls.Set("hello")
+		task2 := GoWait(func(token CancelToken) {
+			assert.False(t, tls.IsSet())
+			assert.Equal(t, "", tls.Get())
+		})
+		task2.Get()
+		assert.Equal(t, "hello", tls.Get())
+	})
+	task.Get()
+}
+
+func TestNewInheritableThreadLocalWithOptions_SameType(t *testing.T) {
+	tls := NewInheritableThreadLocalWithOptions(InheritableThreadLocalOptions[*personCloneable]{Policy: InheritPolicyShare})
+	tls2 := NewInheritableThreadLocal[*personCloneable]()
+	defer tls.Close()
+	defer tls2.Close()
+	value := &personCloneable{Id: 1, Name: "Tim"}
+	task := GoWait(func(token CancelToken) {
+		tls.Set(value)
+		tls2.Set(value)
+		snapshot := Capture()
+		task2 := GoWait(func(token CancelToken) {
+			assert.Same(t, value, tls.Get())
+			assert.NotSame(t, value, tls2.Get())
+			snapshot.Run(func() {
+				assert.Same(t, value, tls.Get())
+				assert.NotSame(t, value, tls2.Get())
+			})
+		})
+		task2.Get()
+	})
+	task.Get()
+}
+
+func TestNewInheritableThreadLocalWithOptions_Unknown(t *testing.T) {
+	assert.PanicsWithValue(t, "unknown inherit policy.", func() {
+		NewInheritableThreadLocalWithOptions(InheritableThreadLocalOptions[int]{Policy: InheritPolicy(-1)})
+	})
+}
@@ -0,0 +1,41 @@
+package routine
+
+// Leak is a value of the non-inheritable ThreadLocal which was set during a unit of work and is still present when the unit ends.
+type Leak struct {
+	// Goid is the id of the goroutine where the value was left.
+	Goid uint64
+
+	// Name is the name of the ThreadLocal, it is empty if the ThreadLocal is not created by the named constructors.
+	Name string
+
+	// Value is the value left in the goroutine.
+	Value any
+
+	// Frames is the stack where the value was set for the last time, the innermost frame first.
+	Frames []Frame
+}
+
+// LeakHandler handles the Leak found when a unit of work ends.
+type LeakHandler func(leak Leak)
+
+// SetLeakHandler enables the leak detection and sets the handler to report the leaks, the leak detection is disabled if the handler is nil.
+// A unit of work is the task created by Go, GoWait, GoWaitResult, WrapTask, WrapWaitTask, WrapWaitResultTask methods or the function executed by Scope.
+// The leak detection records the stack every time a non-inheritable ThreadLocal is set, so it should be enabled for debugging only.
+func SetLeakHandler(handler LeakHandler) {
+	storeLeakHandler(handler)
+}
+
+// GetLeakHandler returns the handler of the leak detection or nil if the leak detection is disabled.
+func GetLeakHandler() LeakHandler {
+	return loadLeakHandler()
+}
+
+// Scope executes the function as a unit of work.
+// If the leak detection is enabled, the non-inheritable ThreadLocals which were set during the function and not removed are reported when the function returns or panics.
+func Scope(fun Runnable) {
+	if fun == nil {
+		panic("fun can not be nil.")
+	}
+	defer beginLeakDetection()()
+	fun()
+}
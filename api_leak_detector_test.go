@@ -0,0 +1,142 @@
+package routine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetLeakHandler(t *testing.T) {
+	assert.Nil(t, GetLeakHandler())
+	//
+	var leaks []Leak
+	SetLeakHandler(func(leak Leak) {
+		leaks = append(leaks, leak)
+	})
+	assert.NotNil(t, GetLeakHandler())
+	//
+	SetLeakHandler(nil)
+	assert.Nil(t, GetLeakHandler())
+	tls := NewThreadLocal[string]()
+	task := GoWait(func(token CancelToken) {
+		tls.Set("leaked")
+	})
+	task.Get()
+	assert.Empty(t, leaks)
+}
+
+func TestScope(t *testing.T) {
+	assert.Panics(t, func() {
+		Scope(nil)
+	})
+	//
+	tls := NewNamedThreadLocal[string]("TestScope")
+	tls2 := NewThreadLocal[int]()
+	tls3 := NewInheritableThreadLocal[string]()
+	defer tls.Close()
+	var leaks []Leak
+	SetLeakHandler(func(leak Leak) {
+		leaks = append(leaks, leak)
+	})
+	defer SetLeakHandler(nil)
+	//
+	task := GoWait(func(token CancelToken) {
+		leaks = nil
+		tls2.Set(1)
+		Scope(func() {
+			tls.Set("leaked")
+			tls2.Remove()
+			tls2.Set(2)
+			tls2.Remove()
+			tls3.Set("inherited")
+		})
+		assert.Len(t, leaks, 1)
+		assert.Equal(t, Goid(), leaks[0].Goid)
+		assert.Equal(t, "TestScope", leaks[0].Name)
+		assert.Equal(t, "leaked", leaks[0].Value)
+		assert.Greater(t, len(leaks[0].Frames), 0)
+		assert.Equal(t, "github.com/timandy/routine.TestScope.func3.1", leaks[0].Frames[0].Function)
+		assert.Equal(t, "leaked", tls.Get())
+		//
+		leaks = nil
+		tls.Remove()
+		Scope(func() {
+			tls.Set("removed")
+			tls.Remove()
+		})
+		assert.Empty(t, leaks)
+	})
+	task.Get()
+}
+
+func TestScope_Nested(t *testing.T) {
+	tls := NewThreadLocal[string]()
+	tls2 := NewThreadLocal[string]()
+	var leaks []Leak
+	SetLeakHandler(func(leak Leak) {
+		leaks = append(leaks, leak)
+	})
+	defer SetLeakHandler(nil)
+	//
+	task := GoWait(func(token CancelToken) {
+		Scope(func() {
+			Scope(func() {
+				tls.Set("inner")
+			})
+			assert.Len(t, leaks, 1)
+			assert.Equal(t, "inner", leaks[0].Value)
+			tls.Remove()
+			tls2.Set("outer")
+		})
+		assert.Len(t, leaks, 2)
+		assert.Equal(t, "outer", leaks[1].Value)
+	})
+	task.Get()
+}
+
+func TestScope_Panic(t *testing.T) {
+	tls := NewThreadLocal[string]()
+	var leaks []Leak
+	SetLeakHandler(func(leak Leak) {
+		leaks = append(leaks, leak)
+	})
+	defer SetLeakHandler(nil)
+	//
+	task := GoWait(func(token CancelToken) {
+		assert.Panics(t, func() {
+			Scope(func() {
+				tls.Set("leaked")
+				panic("error")
+			})
+		})
+		assert.Len(t, leaks, 1)
+	})
+	task.Get()
+}
+
+func TestScope_Task(t *testing.T) {
+	tls := NewThreadLocal[string]()
+	leaks := make(chan Leak, 3)
+	SetLeakHandler(func(leak Leak) {
+		leaks <- leak
+	})
+	defer SetLeakHandler(nil)
+	//
+	task := WrapTask(func() {
+		tls.Set("task")
+	})
+	task.Run()
+	assert.Equal(t, "task", (<-leaks).Value)
+	//
+	GoWait(func(token CancelToken) {
+		tls.Set("wait")
+	}).Get()
+	assert.Equal(t, "wait", (<-leaks).Value)
+	//
+	GoWaitResult(func(token CancelToken) int {
+		tls.Set("result")
+		return 0
+	}).Get()
+	assert.Equal(t, "result", (<-leaks).Value)
+	assert.Len(t, leaks, 0)
+}
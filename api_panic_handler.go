@@ -0,0 +1,34 @@
+package routine
+
+// UncaughtPanicHandler handles the panic which is not caught in the task created by Go or WrapTask methods, or the callback registered by FutureTask.OnComplete method.
+// The goid is the id of the goroutine where the panic occurred.
+type UncaughtPanicHandler func(goid uint64, err RuntimeError)
+
+// SetUncaughtPanicHandler sets the global UncaughtPanicHandler.
+// If the handler is nil, the default handler which writes the error stack to os.Stderr will be used.
+func SetUncaughtPanicHandler(handler UncaughtPanicHandler) {
+	if handler == nil {
+		handler = defaultUncaughtPanicHandler
+	}
+	storeUncaughtPanicHandler(handler)
+}
+
+// GetUncaughtPanicHandler returns the global UncaughtPanicHandler.
+func GetUncaughtPanicHandler() UncaughtPanicHandler {
+	return loadUncaughtPanicHandler()
+}
+
+// SetCrashOnUncaughtPanic sets whether to panic again after the UncaughtPanicHandler invoked.
+// If crash is true, the process will crash like the panic raised in a goroutine started by the go statement.
+// The panic is raised again in a new goroutine after the task has failed, so it can not be recovered by any caller.
+// The process crashes once the new goroutine is scheduled, the delay is not bounded and the other goroutines keep running until then.
+func SetCrashOnUncaughtPanic(crash bool) {
+	storeCrashOnUncaughtPanic(crash)
+}
+
+// WrapTaskWithPanicHandler create a new task and capture the inheritableThreadLocals from the current goroutine.
+// It is the same as WrapTask except that the uncaught panic will be handled by the handler instead of the global UncaughtPanicHandler.
+// If the handler is nil, the global UncaughtPanicHandler will be used.
+func WrapTaskWithPanicHandler(fun Runnable, handler UncaughtPanicHandler) FutureTask[any] {
+	return wrapTask(fun, handler, 1)
+}
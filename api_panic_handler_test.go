@@ -0,0 +1,79 @@
+package routine
+
+import (
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetUncaughtPanicHandler(t *testing.T) {
+	defer SetUncaughtPanicHandler(nil)
+	var goid uint64
+	var err RuntimeError
+	done := make(chan struct{})
+	SetUncaughtPanicHandler(func(g uint64, e RuntimeError) {
+		goid = g
+		err = e
+		close(done)
+	})
+	var taskGoid uint64
+	Go(func() {
+		taskGoid = Goid()
+		panic("error")
+	})
+	<-done
+	assert.Equal(t, taskGoid, goid)
+	assert.Equal(t, "error", err.Message())
+	//
+	SetUncaughtPanicHandler(nil)
+	assert.Equal(t, reflect.ValueOf(defaultUncaughtPanicHandler).Pointer(), reflect.ValueOf(GetUncaughtPanicHandler()).Pointer())
+}
+
+func TestGetUncaughtPanicHandler(t *testing.T) {
+	assert.NotNil(t, GetUncaughtPanicHandler())
+	assert.Equal(t, reflect.ValueOf(defaultUncaughtPanicHandler).Pointer(), reflect.ValueOf(GetUncaughtPanicHandler()).Pointer())
+}
+
+func TestSetCrashOnUncaughtPanic(t *testing.T) {
+	if os.Getenv("ROUTINE_TEST_CRASH") == "1" {
+		SetUncaughtPanicHandler(func(goid uint64, err RuntimeError) {})
+		SetCrashOnUncaughtPanic(true)
+		task := WrapTask(func() {
+			panic("crash error")
+		})
+		task.Run()
+		select {}
+	}
+	cmd := exec.Command(os.Args[0], "-test.run=^TestSetCrashOnUncaughtPanic$")
+	cmd.Env = append(os.Environ(), "ROUTINE_TEST_CRASH=1")
+	output, err := cmd.CombinedOutput()
+	assert.NotNil(t, err)
+	assert.True(t, strings.Contains(string(output), "panic: RuntimeError: crash error"))
+}
+
+func TestWrapTaskWithPanicHandler(t *testing.T) {
+	tracker := NewFileTracker(os.Stderr)
+	tracker.Begin()
+	defer tracker.End()
+	//
+	var err RuntimeError
+	task := WrapTaskWithPanicHandler(func() {
+		panic("error")
+	}, func(goid uint64, e RuntimeError) {
+		err = e
+	})
+	task.Run()
+	assert.True(t, task.IsFailed())
+	assert.Equal(t, "error", err.Message())
+	assert.Equal(t, "", tracker.Value())
+	//
+	task2 := WrapTaskWithPanicHandler(func() {
+		panic("error")
+	}, nil)
+	task2.Run()
+	assert.True(t, strings.HasPrefix(tracker.Value(), "RuntimeError: error"))
+}
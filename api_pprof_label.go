@@ -0,0 +1,70 @@
+package routine
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+// Labels returns a copy of the pprof labels of the current goroutine, returns nil if no label is set.
+// The labels set by pprof.Do, pprof.SetGoroutineLabels or SetLabels can be read in both the default mode and the routinex mode.
+func Labels() map[string]string {
+	return loadLabels().toMap()
+}
+
+// SetLabels replaces the pprof labels of the current goroutine, the labels will be inherited by the sub goroutines like pprof.SetGoroutineLabels.
+// Unlike pprof.SetGoroutineLabels, the threadLocals and inheritableThreadLocals of the current goroutine are kept in the default mode.
+func SetLabels(labels map[string]string) {
+	storeLabels(newLabelMap(labels))
+}
+
+// SetGoroutineLabels sets the pprof labels stored in the context as the labels of the current goroutine.
+// It is the same as pprof.SetGoroutineLabels, but the threadLocals and inheritableThreadLocals of the current goroutine are kept in the default mode.
+func SetGoroutineLabels(ctx context.Context) {
+	if ctx == nil {
+		panic("ctx can not be nil.")
+	}
+	SetLabels(contextLabels(ctx))
+}
+
+// DoWithLabels calls the function with a copy of the context with the given labels added, the labels of the current goroutine are set during the call.
+// It is the same as pprof.Do, but the threadLocals and inheritableThreadLocals of the current goroutine are kept in the default mode.
+// The labels of the current goroutine will be restored when the function returns or panics.
+func DoWithLabels(ctx context.Context, labels pprof.LabelSet, fun func(ctx context.Context)) {
+	if ctx == nil {
+		panic("ctx can not be nil.")
+	}
+	if fun == nil {
+		panic("fun can not be nil.")
+	}
+	backup := loadLabels()
+	defer storeLabels(backup)
+	ctx = pprof.WithLabels(ctx, labels)
+	SetGoroutineLabels(ctx)
+	fun(ctx)
+}
+
+// MirrorLabel mirrors the value of the ThreadLocal into the pprof label of the key, so that the profiles can be sliced by the value.
+// The label is set when the value is set in a goroutine and deleted when the value is removed.
+// The labels are also synchronized when the threadLocals are replaced, e.g. a task started by Go or an Executor, or a Snapshot restored.
+// A key can be mirrored from only one ThreadLocal, the previous mirror of the key is replaced.
+// The ThreadLocal must be created by this package.
+func MirrorLabel(key string, tls ThreadLocal[string]) {
+	if len(key) == 0 {
+		panic("key can not be empty.")
+	}
+	switch t := tls.(type) {
+	case *threadLocal[string]:
+		addLabelMirror(key, false, t.index, t.version)
+	case *inheritableThreadLocal[string]:
+		addLabelMirror(key, true, t.index, t.version)
+	case nil:
+		panic("tls can not be nil.")
+	default:
+		panic("tls must be created by routine.")
+	}
+}
+
+// UnmirrorLabel stops mirroring the ThreadLocal into the pprof label of the key, the labels already set are kept.
+func UnmirrorLabel(key string) {
+	removeLabelMirror(key)
+}
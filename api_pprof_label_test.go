@@ -0,0 +1,301 @@
+package routine
+
+import (
+	"bytes"
+	"context"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabels(t *testing.T) {
+	task := GoWait(func(token CancelToken) {
+		assert.Nil(t, Labels())
+		//
+		pprof.SetGoroutineLabels(pprof.WithLabels(context.Background(), pprof.Labels("key", "value")))
+		assert.Equal(t, map[string]string{"key": "value"}, Labels())
+		//
+		pprof.Do(context.Background(), pprof.Labels("key2", "value2"), func(ctx context.Context) {
+			assert.Equal(t, map[string]string{"key2": "value2"}, Labels())
+		})
+		assert.Nil(t, Labels())
+	})
+	task.Get()
+}
+
+func TestSetLabels(t *testing.T) {
+	tls := NewThreadLocal[string]()
+	tls2 := NewInheritableThreadLocal[string]()
+	task := GoWait(func(token CancelToken) {
+		tls.Set("hello")
+		tls2.Set("world")
+		SetLabels(map[string]string{"tenant": "t1", "user": "u1"})
+		assert.Equal(t, map[string]string{"tenant": "t1", "user": "u1"}, Labels())
+		assert.Equal(t, "hello", tls.Get())
+		assert.Equal(t, "world", tls2.Get())
+		//
+		labels := map[string]string{}
+		ctx := pprof.WithLabels(context.Background(), pprof.Labels("key", "value"))
+		pprof.SetGoroutineLabels(ctx)
+		pprof.ForLabels(ctx, func(key, value string) bool {
+			labels[key] = value
+			return true
+		})
+		assert.Equal(t, labels, Labels())
+		//
+		SetLabels(nil)
+		assert.Nil(t, Labels())
+	})
+	task.Get()
+}
+
+func TestSetLabels_Supplier(t *testing.T) {
+	tls := NewThreadLocalWithInitial[string](func() string {
+		SetLabels(map[string]string{"supplier": "Get"})
+		return "Get"
+	})
+	tls2 := NewInheritableThreadLocal[string]()
+	defer tls.Close()
+	defer tls2.Close()
+	task := GoWait(func(token CancelToken) {
+		assert.Equal(t, "Get", tls.Get())
+		assert.Equal(t, "Get", tls.Get())
+		assert.Equal(t, map[string]string{"supplier": "Get"}, Labels())
+	})
+	task.Get()
+	//
+	task2 := GoWait(func(token CancelToken) {
+		value := tls2.GetOrSet(func() string {
+			SetLabels(map[string]string{"supplier": "GetOrSet"})
+			return "GetOrSet"
+		})
+		assert.Equal(t, "GetOrSet", value)
+		assert.Equal(t, "GetOrSet", tls2.Get())
+		assert.Equal(t, map[string]string{"supplier": "GetOrSet"}, Labels())
+	})
+	task2.Get()
+	//
+	task3 := GoWait(func(token CancelToken) {
+		value := tls2.Update(func(old string) string {
+			SetLabels(map[string]string{"supplier": "Update"})
+			return old + "Update"
+		})
+		assert.Equal(t, "Update", value)
+		assert.Equal(t, "Update", tls2.Get())
+		assert.Equal(t, map[string]string{"supplier": "Update"}, Labels())
+	})
+	task3.Get()
+	//
+	task4 := GoWait(func(token CancelToken) {
+		assert.Equal(t, "Get", tls.Swap("Swap"))
+		assert.Equal(t, "Swap", tls.Get())
+	})
+	task4.Get()
+}
+
+func TestSetLabels_Inherit(t *testing.T) {
+	tls := NewInheritableThreadLocal[string]()
+	task := GoWait(func(token CancelToken) {
+		tls.Set("hello")
+		SetLabels(map[string]string{"tenant": "t1"})
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			assert.Equal(t, map[string]string{"tenant": "t1"}, Labels())
+			SetLabels(map[string]string{"tenant": "t2"})
+		}()
+		<-done
+		task2 := GoWait(func(token CancelToken) {
+			assert.Equal(t, map[string]string{"tenant": "t1"}, Labels())
+			assert.Equal(t, "hello", tls.Get())
+		})
+		task2.Get()
+		assert.Equal(t, map[string]string{"tenant": "t1"}, Labels())
+	})
+	task.Get()
+}
+
+func TestSetLabels_Profile(t *testing.T) {
+	started := make(chan struct{})
+	stop := make(chan struct{})
+	go func() {
+		SetLabels(map[string]string{"tenant": "TestSetLabels_Profile"})
+		close(started)
+		<-stop
+	}()
+	<-started
+	defer close(stop)
+	buf := &bytes.Buffer{}
+	assert.Nil(t, pprof.Lookup("goroutine").WriteTo(buf, 1))
+	assert.Contains(t, buf.String(), `"tenant":"TestSetLabels_Profile"`)
+}
+
+func TestSetGoroutineLabels(t *testing.T) {
+	assert.Panics(t, func() {
+		SetGoroutineLabels(nil) //nolint:staticcheck
+	})
+	//
+	tls := NewThreadLocal[string]()
+	task := GoWait(func(token CancelToken) {
+		tls.Set("hello")
+		SetGoroutineLabels(pprof.WithLabels(context.Background(), pprof.Labels("key", "value")))
+		assert.Equal(t, map[string]string{"key": "value"}, Labels())
+		assert.Equal(t, "hello", tls.Get())
+		//
+		SetGoroutineLabels(context.Background())
+		assert.Nil(t, Labels())
+		assert.Equal(t, "hello", tls.Get())
+	})
+	task.Get()
+}
+
+func TestDoWithLabels(t *testing.T) {
+	assert.Panics(t, func() {
+		DoWithLabels(nil, pprof.Labels(), func(ctx context.Context) {}) //nolint:staticcheck
+	})
+	assert.Panics(t, func() {
+		DoWithLabels(context.Background(), pprof.Labels(), nil)
+	})
+	//
+	tls := NewThreadLocal[string]()
+	task := GoWait(func(token CancelToken) {
+		tls.Set("hello")
+		SetLabels(map[string]string{"outer": "value"})
+		parent := pprof.WithLabels(context.Background(), pprof.Labels("key", "value"))
+		DoWithLabels(parent, pprof.Labels("key2", "value2"), func(ctx context.Context) {
+			value, ok := pprof.Label(ctx, "key2")
+			assert.True(t, ok)
+			assert.Equal(t, "value2", value)
+			assert.Equal(t, map[string]string{"key": "value", "key2": "value2"}, Labels())
+			assert.Equal(t, "hello", tls.Get())
+			tls.Set("hi")
+		})
+		assert.Equal(t, map[string]string{"outer": "value"}, Labels())
+		assert.Equal(t, "hi", tls.Get())
+		//
+		assert.Panics(t, func() {
+			DoWithLabels(context.Background(), pprof.Labels("key", "value"), func(ctx context.Context) {
+				panic("error")
+			})
+		})
+		assert.Equal(t, map[string]string{"outer": "value"}, Labels())
+	})
+	task.Get()
+}
+
+func TestMirrorLabel(t *testing.T) {
+	assert.Panics(t, func() {
+		MirrorLabel("", NewThreadLocal[string]())
+	})
+	assert.Panics(t, func() {
+		MirrorLabel("key", nil)
+	})
+	assert.Panics(t, func() {
+		MirrorLabel("key", &mockThreadLocal[string]{})
+	})
+	//
+	tls := NewThreadLocal[string]()
+	tls2 := NewInheritableThreadLocal[string]()
+	defer tls.Close()
+	defer tls2.Close()
+	MirrorLabel("TestMirrorLabel_user", tls)
+	MirrorLabel("TestMirrorLabel_tenant", tls2)
+	defer UnmirrorLabel("TestMirrorLabel_user")
+	defer UnmirrorLabel("TestMirrorLabel_tenant")
+	task := GoWait(func(token CancelToken) {
+		tls.Set("u1")
+		tls2.Set("t1")
+		assert.Equal(t, map[string]string{"TestMirrorLabel_user": "u1", "TestMirrorLabel_tenant": "t1"}, Labels())
+		assert.Equal(t, "u1", tls.Get())
+		assert.Equal(t, "t1", tls2.Get())
+		//
+		task2 := GoWait(func(token CancelToken) {
+			assert.Equal(t, map[string]string{"TestMirrorLabel_tenant": "t1"}, Labels())
+			assert.False(t, tls.IsSet())
+			assert.Equal(t, "t1", tls2.Get())
+		})
+		task2.Get()
+		//
+		tls.Remove()
+		assert.Equal(t, map[string]string{"TestMirrorLabel_tenant": "t1"}, Labels())
+		//
+		UnmirrorLabel("TestMirrorLabel_tenant")
+		tls2.Set("t2")
+		assert.Equal(t, map[string]string{"TestMirrorLabel_tenant": "t1"}, Labels())
+	})
+	task.Get()
+}
+
+type mockThreadLocal[T any] struct {
+	ThreadLocal[T]
+}
+
+func TestMirrorLabel_Executor(t *testing.T) {
+	tls := NewInheritableThreadLocal[string]()
+	defer tls.Close()
+	MirrorLabel("TestMirrorLabel_Executor", tls)
+	defer UnmirrorLabel("TestMirrorLabel_Executor")
+	executor := NewFixedExecutor(1, 8)
+	defer executor.Shutdown()
+	task := GoWait(func(token CancelToken) {
+		tls.Set("A")
+		executor.Submit(func() {
+			assert.Equal(t, map[string]string{"TestMirrorLabel_Executor": "A"}, Labels())
+		}).Get()
+	})
+	task.Get()
+	task2 := GoWait(func(token CancelToken) {
+		executor.Submit(func() {
+			assert.False(t, tls.IsSet())
+			assert.Nil(t, Labels())
+		}).Get()
+	})
+	task2.Get()
+}
+
+func TestMirrorLabel_Snapshot(t *testing.T) {
+	tls := NewInheritableThreadLocal[string]()
+	defer tls.Close()
+	MirrorLabel("TestMirrorLabel_Snapshot", tls)
+	defer UnmirrorLabel("TestMirrorLabel_Snapshot")
+	task := GoWait(func(token CancelToken) {
+		tls.Set("X")
+		snapshot := Capture()
+		EmptySnapshot().Run(func() {
+			assert.False(t, tls.IsSet())
+			assert.Nil(t, Labels())
+			snapshot.Run(func() {
+				assert.Equal(t, "X", tls.Get())
+				assert.Equal(t, map[string]string{"TestMirrorLabel_Snapshot": "X"}, Labels())
+			})
+			assert.Nil(t, Labels())
+		})
+		assert.Equal(t, map[string]string{"TestMirrorLabel_Snapshot": "X"}, Labels())
+	})
+	task.Get()
+}
+
+func TestThreadLocal_PprofDo(t *testing.T) {
+	tls := NewThreadLocal[string]()
+	defer tls.Close()
+	task := GoWait(func(token CancelToken) {
+		tls.Set("Hello")
+		pprof.Do(context.Background(), pprof.Labels("key", "value"), func(ctx context.Context) {
+			// the labels of g are replaced by runtime/pprof, so the thread stored in the labels is lost in the default mode
+			assert.Equal(t, routinexEnabled, tls.IsSet())
+		})
+		assert.Equal(t, routinexEnabled, tls.IsSet())
+		//
+		tls.Set("World")
+		pprof.SetGoroutineLabels(pprof.WithLabels(context.Background(), pprof.Labels("key", "value")))
+		assert.Equal(t, routinexEnabled, tls.IsSet())
+		//
+		tls.Set("Hello")
+		DoWithLabels(context.Background(), pprof.Labels("key", "value"), func(ctx context.Context) {
+			assert.Equal(t, "Hello", tls.Get())
+		})
+		assert.Equal(t, "Hello", tls.Get())
+	})
+	task.Get()
+}
@@ -15,11 +15,9 @@ type CancelCallable[TResult any] func(token CancelToken) TResult
 // WrapTask create a new task and capture the inheritableThreadLocals from the current goroutine.
 // This function returns a FutureTask instance, but the return task will not run automatically.
 // You can run it in a sub-goroutine or goroutine-pool by FutureTask.Run method, wait by FutureTask.Get or FutureTask.GetWithTimeout method.
-// When the returned task run panic will be caught and error stack will be printed, the panic will be trigger again when calling FutureTask.Get or FutureTask.GetWithTimeout method.
+// When the returned task run panic will be caught and handled by the UncaughtPanicHandler, the panic will be trigger again when calling FutureTask.Get or FutureTask.GetWithTimeout method.
 func WrapTask(fun Runnable) FutureTask[any] {
-	ctx := createInheritedMap()
-	callable := inheritedTask{context: ctx, function: fun}.run
-	return NewFutureTask[any](callable)
+	return wrapTask(fun, nil, 1)
 }
 
 // WrapWaitTask create a new task and capture the inheritableThreadLocals from the current goroutine.
@@ -27,9 +25,7 @@ func WrapTask(fun Runnable) FutureTask[any] {
 // You can run it in a sub-goroutine or goroutine-pool by FutureTask.Run method, wait by FutureTask.Get or FutureTask.GetWithTimeout method.
 // When the returned task run panic will be caught, the panic will be trigger again when calling FutureTask.Get or FutureTask.GetWithTimeout method.
 func WrapWaitTask(fun CancelRunnable) FutureTask[any] {
-	ctx := createInheritedMap()
-	callable := inheritedWaitTask{context: ctx, function: fun}.run
-	return NewFutureTask[any](callable)
+	return wrapWaitTask(fun, 1)
 }
 
 // WrapWaitResultTask create a new task and capture the inheritableThreadLocals from the current goroutine.
@@ -37,15 +33,13 @@ func WrapWaitTask(fun CancelRunnable) FutureTask[any] {
 // You can run it in a sub-goroutine or goroutine-pool by FutureTask.Run method, wait and get result by FutureTask.Get or FutureTask.GetWithTimeout method.
 // When the returned task run panic will be caught, the panic will be trigger again when calling FutureTask.Get or FutureTask.GetWithTimeout method.
 func WrapWaitResultTask[TResult any](fun CancelCallable[TResult]) FutureTask[TResult] {
-	ctx := createInheritedMap()
-	callable := inheritedWaitResultTask[TResult]{context: ctx, function: fun}.run
-	return NewFutureTask[TResult](callable)
+	return wrapWaitResultTask(fun, 1)
 }
 
 // Go starts a new goroutine, and copy inheritableThreadLocals from current goroutine.
-// This function will auto invoke the func and print error stack when panic occur in goroutine.
+// This function will auto invoke the func and handle the panic by the UncaughtPanicHandler when panic occur in goroutine.
 func Go(fun Runnable) {
-	task := WrapTask(fun)
+	task := wrapTask(fun, nil, 1)
 	go task.Run()
 }
 
@@ -53,7 +47,7 @@ func Go(fun Runnable) {
 // This function will auto invoke the func and return a FutureTask instance, so we can wait by FutureTask.Get or FutureTask.GetWithTimeout method.
 // If panic occur in goroutine, The panic will be trigger again when calling FutureTask.Get or FutureTask.GetWithTimeout method.
 func GoWait(fun CancelRunnable) FutureTask[any] {
-	task := WrapWaitTask(fun)
+	task := wrapWaitTask(fun, 1)
 	go task.Run()
 	return task
 }
@@ -62,7 +56,7 @@ func GoWait(fun CancelRunnable) FutureTask[any] {
 // This function will auto invoke the func and return a FutureTask instance, so we can wait and get result by FutureTask.Get or FutureTask.GetWithTimeout method.
 // If panic occur in goroutine, The panic will be trigger again when calling FutureTask.Get or FutureTask.GetWithTimeout method.
 func GoWaitResult[TResult any](fun CancelCallable[TResult]) FutureTask[TResult] {
-	task := WrapWaitResultTask(fun)
+	task := wrapWaitResultTask(fun, 1)
 	go task.Run()
 	return task
 }
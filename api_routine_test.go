@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"testing"
@@ -137,7 +138,7 @@ func TestWrapTask_HasContext(t *testing.T) {
 }
 
 func TestWrapTask_Complete_ThenFail(t *testing.T) {
-	tracker := NewFileTracker(os.Stdout)
+	tracker := NewFileTracker(os.Stderr)
 	tracker.Begin()
 	defer tracker.End()
 	//
@@ -343,7 +344,7 @@ func TestWrapWaitTask_HasContext_Cancel(t *testing.T) {
 }
 
 func TestWrapWaitTask_Complete_ThenFail(t *testing.T) {
-	tracker := NewFileTracker(os.Stdout)
+	tracker := NewFileTracker(os.Stderr)
 	tracker.Begin()
 	defer tracker.End()
 	//
@@ -553,7 +554,7 @@ func TestWrapWaitResultTask_HasContext_Cancel(t *testing.T) {
 }
 
 func TestWrapWaitResultTask_Complete_ThenFail(t *testing.T) {
-	tracker := NewFileTracker(os.Stdout)
+	tracker := NewFileTracker(os.Stderr)
 	tracker.Begin()
 	defer tracker.End()
 	//
@@ -587,7 +588,7 @@ func TestWrapWaitResultTask_Complete_ThenFail(t *testing.T) {
 }
 
 func TestGo_Error(t *testing.T) {
-	tracker := NewFileTracker(os.Stdout)
+	tracker := NewFileTracker(os.Stderr)
 	tracker.Begin()
 	defer tracker.End()
 	//
@@ -606,31 +607,35 @@ func TestGo_Error(t *testing.T) {
 	//
 	time.Sleep(10 * time.Millisecond)
 	lines := strings.Split(tracker.Value(), newLine)
-	assert.Equal(t, 7, len(lines))
+	assert.Equal(t, 8, len(lines))
 	//
 	line := lines[0]
 	assert.Equal(t, "RuntimeError: error", line)
 	//
 	line = lines[1]
 	assert.True(t, strings.HasPrefix(line, "   at github.com/timandy/routine.TestGo_Error."))
-	assert.True(t, strings.HasSuffix(line, "api_routine_test.go:601"))
+	assert.True(t, strings.HasSuffix(line, "api_routine_test.go:602"))
 	//
 	line = lines[2]
 	assert.True(t, strings.HasPrefix(line, "   at github.com/timandy/routine.inheritedTask.run()"))
-	assert.True(t, strings.HasSuffix(line, "routine.go:24"))
+	assert.True(t, strings.HasSuffix(line, "routine.go:29"))
 	//
 	line = lines[3]
 	assert.True(t, strings.HasPrefix(line, "   at github.com/timandy/routine.(*futureTask[...]).Run()"))
-	assert.True(t, strings.HasSuffix(line, "future_task.go:108"))
+	assert.True(t, strings.HasSuffix(line, "future_task.go:201"))
 	//
 	line = lines[4]
 	assert.Equal(t, "   --- End of error stack trace ---", line)
 	//
 	line = lines[5]
 	assert.True(t, strings.HasPrefix(line, "   created by github.com/timandy/routine.Go()"))
-	assert.True(t, strings.HasSuffix(line, "api_routine.go:49"))
+	assert.True(t, strings.HasSuffix(line, "api_routine.go:43"))
 	//
 	line = lines[6]
+	assert.True(t, strings.HasPrefix(line, "   started by goroutine "+strconv.FormatUint(Goid(), 10)+" at github.com/timandy/routine.TestGo_Error.func1()"))
+	assert.True(t, strings.HasSuffix(line, "api_routine_test.go:599"))
+	//
+	line = lines[7]
 	assert.Equal(t, "", line)
 }
 
@@ -642,6 +647,7 @@ func TestGo_Nil(t *testing.T) {
 	wg.Add(1)
 	Go(func() {
 		assert.Nil(t, createInheritedMap())
+		assert.Equal(t, 1, len(Ancestors()))
 		run = true
 		wg.Done()
 	})
@@ -716,29 +722,33 @@ func TestGoWait_Error(t *testing.T) {
 		assert.Implements(t, (*RuntimeError)(nil), cause)
 		err := cause.(RuntimeError)
 		lines := strings.Split(err.Error(), newLine)
-		assert.Equal(t, 6, len(lines))
+		assert.Equal(t, 7, len(lines))
 		//
 		line := lines[0]
 		assert.Equal(t, "RuntimeError: error", line)
 		//
 		line = lines[1]
 		assert.True(t, strings.HasPrefix(line, "   at github.com/timandy/routine.TestGoWait_Error."))
-		assert.True(t, strings.HasSuffix(line, "api_routine_test.go:706"))
+		assert.True(t, strings.HasSuffix(line, "api_routine_test.go:712"))
 		//
 		line = lines[2]
 		assert.True(t, strings.HasPrefix(line, "   at github.com/timandy/routine.inheritedWaitTask.run()"))
-		assert.True(t, strings.HasSuffix(line, "routine.go:44"))
+		assert.True(t, strings.HasSuffix(line, "routine.go:57"))
 		//
 		line = lines[3]
 		assert.True(t, strings.HasPrefix(line, "   at github.com/timandy/routine.(*futureTask[...]).Run()"))
-		assert.True(t, strings.HasSuffix(line, "future_task.go:108"))
+		assert.True(t, strings.HasSuffix(line, "future_task.go:201"))
 		//
 		line = lines[4]
 		assert.Equal(t, "   --- End of error stack trace ---", line)
 		//
 		line = lines[5]
 		assert.True(t, strings.HasPrefix(line, "   created by github.com/timandy/routine.GoWait()"))
-		assert.True(t, strings.HasSuffix(line, "api_routine.go:57"))
+		assert.True(t, strings.HasSuffix(line, "api_routine.go:51"))
+		//
+		line = lines[6]
+		assert.True(t, strings.HasPrefix(line, "   started by goroutine "+strconv.FormatUint(Goid(), 10)+" at github.com/timandy/routine.TestGoWait_Error()"))
+		assert.True(t, strings.HasSuffix(line, "api_routine_test.go:710"))
 	}()
 	task.Get()
 }
@@ -749,6 +759,7 @@ func TestGoWait_Nil(t *testing.T) {
 	run := false
 	task := GoWait(func(token CancelToken) {
 		assert.Nil(t, createInheritedMap())
+		assert.Equal(t, 1, len(Ancestors()))
 		run = true
 	})
 	assert.Nil(t, task.Get())
@@ -818,37 +829,41 @@ func TestGoWaitResult_Error(t *testing.T) {
 		assert.Implements(t, (*RuntimeError)(nil), cause)
 		err := cause.(RuntimeError)
 		lines := strings.Split(err.Error(), newLine)
-		assert.True(t, len(lines) == 6 || len(lines) == 7)
+		assert.True(t, len(lines) == 7 || len(lines) == 8)
 		//
 		line := lines[0]
 		assert.Equal(t, "RuntimeError: error", line)
 		//
 		line = lines[1]
 		assert.True(t, strings.HasPrefix(line, "   at github.com/timandy/routine.TestGoWaitResult_Error."))
-		assert.True(t, strings.HasSuffix(line, "api_routine_test.go:806"))
+		assert.True(t, strings.HasSuffix(line, "api_routine_test.go:817"))
 		//
 		line = lines[2]
 		assert.True(t, strings.HasPrefix(line, "   at github.com/timandy/routine.inheritedWaitResultTask[...].run()"))
-		assert.True(t, strings.HasSuffix(line, "routine.go:64"))
+		assert.True(t, strings.HasSuffix(line, "routine.go:85"))
 		//
 		lineOffset := 0
-		if len(lines) == 7 {
+		if len(lines) == 8 {
 			line = lines[3+lineOffset]
 			lineOffset = 1
-			assert.True(t, strings.HasPrefix(line, "   at github.com/timandy/routine.WrapWaitResultTask[...].func1()"))
-			assert.True(t, strings.HasSuffix(line, "api_routine.go:41"))
+			assert.True(t, strings.HasPrefix(line, "   at github.com/timandy/routine.wrapWaitResultTask[...].func1()"))
+			assert.True(t, strings.HasSuffix(line, "routine.go:108"))
 		}
 		//
 		line = lines[3+lineOffset]
 		assert.True(t, strings.HasPrefix(line, "   at github.com/timandy/routine.(*futureTask[...]).Run()"))
-		assert.True(t, strings.HasSuffix(line, "future_task.go:108"))
+		assert.True(t, strings.HasSuffix(line, "future_task.go:201"))
 		//
 		line = lines[4+lineOffset]
 		assert.Equal(t, "   --- End of error stack trace ---", line)
 		//
 		line = lines[5+lineOffset]
 		assert.True(t, strings.HasPrefix(line, "   created by github.com/timandy/routine.GoWaitResult[...]()"))
-		assert.True(t, strings.HasSuffix(line, "api_routine.go:66"))
+		assert.True(t, strings.HasSuffix(line, "api_routine.go:60"))
+		//
+		line = lines[6+lineOffset]
+		assert.True(t, strings.HasPrefix(line, "   started by goroutine "+strconv.FormatUint(Goid(), 10)+" at github.com/timandy/routine.TestGoWaitResult_Error()"))
+		assert.True(t, strings.HasSuffix(line, "api_routine_test.go:814"))
 	}()
 	task.Get()
 }
@@ -859,6 +874,7 @@ func TestGoWaitResult_Nil(t *testing.T) {
 	run := false
 	task := GoWaitResult(func(token CancelToken) bool {
 		assert.Nil(t, createInheritedMap())
+		assert.Equal(t, 1, len(Ancestors()))
 		run = true
 		return true
 	})
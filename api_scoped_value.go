@@ -0,0 +1,54 @@
+package routine
+
+// ScopedValue provides an immutable goroutine-local value which is bound only during the execution of a function.
+// The binding is visible in the current goroutine and in the goroutines or tasks started by Go, GoWait, GoWaitResult, WrapTask, WrapWaitTask, WrapWaitResultTask methods during the function.
+// The binding is removed automatically when the function returns or panics, the nested bindings shadow the outer ones.
+type ScopedValue[T any] interface {
+	// Get returns the bound value, it panics if the ScopedValue is not bound in the current goroutine.
+	Get() T
+
+	// IsBound returns true if the ScopedValue is bound in the current goroutine.
+	IsBound() bool
+
+	// OrElse returns the bound value if the ScopedValue is bound, otherwise returns the other.
+	OrElse(other T) T
+
+	// Where create and returns a ScopedBinding which binds the value to this ScopedValue.
+	Where(value T) ScopedBinding
+
+	// Close releases the index of this ScopedValue, so that it can be reused by a ThreadLocal or ScopedValue created later.
+	// The ScopedValue should not be used after closed.
+	Close()
+}
+
+// ScopedBinding is a value bound to a ScopedValue, the binding takes effect only during the methods Run and Call.
+type ScopedBinding interface {
+	// Run executes the function with the value bound, the previous binding will be restored when the function returns or panics.
+	Run(fun Runnable)
+
+	// Call executes the function with the value bound and returns the result of the function.
+	// The previous binding will be restored when the function returns or panics.
+	Call(fun Callable[any]) any
+}
+
+// NewScopedValue create and return a new ScopedValue instance which is not bound in any goroutine.
+func NewScopedValue[T any]() ScopedValue[T] {
+	index, version := acquireInheritableThreadLocalIndex()
+	return &scopedValue[T]{index: index, version: version}
+}
+
+// CallScoped executes the function with the value of the binding bound and returns the result of the function.
+// It is the same as ScopedBinding.Call but keeps the type of the result.
+func CallScoped[TResult any](binding ScopedBinding, fun Callable[TResult]) TResult {
+	if binding == nil {
+		panic("binding can not be nil.")
+	}
+	if fun == nil {
+		panic("fun can not be nil.")
+	}
+	var result TResult
+	binding.Run(func() {
+		result = fun()
+	})
+	return result
+}
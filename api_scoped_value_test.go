@@ -0,0 +1,152 @@
+package routine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewScopedValue(t *testing.T) {
+	sv := NewScopedValue[string]()
+	task := GoWait(func(token CancelToken) {
+		assert.False(t, sv.IsBound())
+		assert.Equal(t, "other", sv.OrElse("other"))
+		assert.PanicsWithValue(t, "ScopedValue is not bound.", func() {
+			sv.Get()
+		})
+	})
+	task.Get()
+}
+
+func TestScopedBinding_Run(t *testing.T) {
+	sv := NewScopedValue[string]()
+	task := GoWait(func(token CancelToken) {
+		sv.Where("hello").Run(func() {
+			assert.True(t, sv.IsBound())
+			assert.Equal(t, "hello", sv.Get())
+			assert.Equal(t, "hello", sv.OrElse("other"))
+		})
+		assert.False(t, sv.IsBound())
+		//
+		assert.Panics(t, func() {
+			sv.Where("hello").Run(nil)
+		})
+	})
+	task.Get()
+}
+
+func TestScopedBinding_Call(t *testing.T) {
+	sv := NewScopedValue[int]()
+	task := GoWait(func(token CancelToken) {
+		result := sv.Where(1).Call(func() any {
+			return sv.Get() + 1
+		})
+		assert.Equal(t, 2, result)
+		assert.False(t, sv.IsBound())
+		//
+		assert.Panics(t, func() {
+			sv.Where(1).Call(nil)
+		})
+	})
+	task.Get()
+}
+
+func TestScopedBinding_Nested(t *testing.T) {
+	sv := NewScopedValue[string]()
+	sv2 := NewScopedValue[int]()
+	task := GoWait(func(token CancelToken) {
+		sv.Where("outer").Run(func() {
+			sv2.Where(1).Run(func() {
+				sv.Where("inner").Run(func() {
+					assert.Equal(t, "inner", sv.Get())
+					assert.Equal(t, 1, sv2.Get())
+				})
+				assert.Equal(t, "outer", sv.Get())
+			})
+			assert.False(t, sv2.IsBound())
+			assert.Equal(t, "outer", sv.Get())
+		})
+		assert.False(t, sv.IsBound())
+	})
+	task.Get()
+}
+
+func TestScopedBinding_Panic(t *testing.T) {
+	sv := NewScopedValue[string]()
+	task := GoWait(func(token CancelToken) {
+		sv.Where("outer").Run(func() {
+			assert.Panics(t, func() {
+				sv.Where("inner").Run(func() {
+					panic("error")
+				})
+			})
+			assert.Equal(t, "outer", sv.Get())
+		})
+		assert.False(t, sv.IsBound())
+	})
+	task.Get()
+}
+
+func TestScopedBinding_Inherit(t *testing.T) {
+	sv := NewScopedValue[*personCloneable]()
+	value := &personCloneable{Id: 1, Name: "Tim"}
+	task := GoWait(func(token CancelToken) {
+		var wrapped FutureTask[any]
+		sv.Where(value).Run(func() {
+			task2 := GoWait(func(token CancelToken) {
+				assert.Same(t, value, sv.Get())
+			})
+			task2.Get()
+			wrapped = WrapTask(func() {
+				assert.Same(t, value, sv.Get())
+			})
+		})
+		assert.False(t, sv.IsBound())
+		wrapped.Run()
+		assert.False(t, sv.IsBound())
+		//
+		task3 := GoWait(func(token CancelToken) {
+			assert.False(t, sv.IsBound())
+		})
+		task3.Get()
+	})
+	task.Get()
+}
+
+func TestCallScoped(t *testing.T) {
+	sv := NewScopedValue[int]()
+	task := GoWait(func(token CancelToken) {
+		result := CallScoped(sv.Where(1), func() string {
+			assert.Equal(t, 1, sv.Get())
+			return "done"
+		})
+		assert.Equal(t, "done", result)
+		assert.False(t, sv.IsBound())
+		//
+		assert.Panics(t, func() {
+			CallScoped[string](nil, func() string { return "" })
+		})
+		assert.Panics(t, func() {
+			CallScoped[string](sv.Where(1), nil)
+		})
+	})
+	task.Get()
+}
+
+func TestScopedValue_Close(t *testing.T) {
+	sv := NewScopedValue[string]()
+	impl := sv.(*scopedValue[string])
+	assert.False(t, inheritableThreadLocalIndexes.isStale(impl.index, impl.version))
+	sv.Close()
+	sv.Close()
+	assert.True(t, inheritableThreadLocalIndexes.isStale(impl.index, impl.version))
+	//
+	sv2 := NewScopedValue[string]()
+	sv2.Where("Hello").Run(func() {
+		assert.Equal(t, "Hello", sv2.Get())
+		sv2.Close()
+		tls2 := NewInheritableThreadLocal[string]()
+		defer tls2.Close()
+		assert.Equal(t, "", tls2.Get())
+	})
+}
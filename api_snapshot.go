@@ -0,0 +1,30 @@
+package routine
+
+// Snapshot is a copy of the goroutine-local context, it can be restored in any goroutine.
+type Snapshot interface {
+	// Restore installs a copy of the snapshot into the current goroutine and returns a function to undo it.
+	// The values set after restored will be discarded when undo.
+	// The InheritPolicy of the ThreadLocals is applied only once when captured,
+	// but the Cloneable values with InheritPolicyClone are cloned again on every restore, so that the restores never share them.
+	Restore() func()
+
+	// Run executes the function with the snapshot restored, the previous context will be restored when the function returns or panics.
+	Run(fun Runnable)
+}
+
+// Capture create a new Snapshot and capture the inheritableThreadLocals from the current goroutine.
+// The threadLocals will be empty when the returned snapshot is restored, the same as the goroutines started by Go, GoWait, GoWaitResult methods.
+func Capture() Snapshot {
+	return captureSnapshot(false)
+}
+
+// CaptureAll create a new Snapshot and capture both the threadLocals and inheritableThreadLocals from the current goroutine.
+func CaptureAll() Snapshot {
+	return captureSnapshot(true)
+}
+
+// EmptySnapshot returns a Snapshot without any threadLocals and inheritableThreadLocals.
+// It can be used to run a function in a clean goroutine-local context, e.g. to serve a request in a reused goroutine.
+func EmptySnapshot() Snapshot {
+	return emptySnapshot
+}
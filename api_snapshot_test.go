@@ -0,0 +1,108 @@
+package routine
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCapture(t *testing.T) {
+	tls := NewThreadLocal[string]()
+	inheritableTls := NewInheritableThreadLocal[string]()
+	tls.Set("Hello")
+	inheritableTls.Set("World")
+	snapshot := Capture()
+	//
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		assert.Equal(t, "", tls.Get())
+		assert.Equal(t, "", inheritableTls.Get())
+		snapshot.Run(func() {
+			assert.Equal(t, "", tls.Get())
+			assert.Equal(t, "World", inheritableTls.Get())
+			inheritableTls.Set("Modified")
+		})
+		assert.Equal(t, "", tls.Get())
+		assert.Equal(t, "", inheritableTls.Get())
+	}()
+	wg.Wait()
+	//
+	snapshot.Run(func() {
+		assert.Equal(t, "", tls.Get())
+		assert.Equal(t, "World", inheritableTls.Get())
+	})
+	assert.Equal(t, "Hello", tls.Get())
+	assert.Equal(t, "World", inheritableTls.Get())
+}
+
+func TestCaptureAll(t *testing.T) {
+	tls := NewThreadLocal[string]()
+	inheritableTls := NewInheritableThreadLocal[string]()
+	tls.Set("Hello")
+	inheritableTls.Set("World")
+	snapshot := CaptureAll()
+	tls.Set("Hello2")
+	inheritableTls.Set("World2")
+	//
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		undo := snapshot.Restore()
+		assert.Equal(t, "Hello", tls.Get())
+		assert.Equal(t, "World", inheritableTls.Get())
+		tls.Set("Modified")
+		undo()
+		assert.Equal(t, "", tls.Get())
+		assert.Equal(t, "", inheritableTls.Get())
+	}()
+	wg.Wait()
+	//
+	undo := snapshot.Restore()
+	assert.Equal(t, "Hello", tls.Get())
+	assert.Equal(t, "World", inheritableTls.Get())
+	undo()
+	assert.Equal(t, "Hello2", tls.Get())
+	assert.Equal(t, "World2", inheritableTls.Get())
+}
+
+func TestSnapshot_Panic(t *testing.T) {
+	tls := NewInheritableThreadLocal[string]()
+	tls.Set("Hello")
+	snapshot := Capture()
+	tls.Set("World")
+	assert.Panics(t, func() {
+		snapshot.Run(func() {
+			assert.Equal(t, "Hello", tls.Get())
+			panic("error")
+		})
+	})
+	assert.Equal(t, "World", tls.Get())
+}
+
+func TestEmptySnapshot(t *testing.T) {
+	tls := NewThreadLocal[string]()
+	inheritableTls := NewInheritableThreadLocal[string]()
+	task := GoWait(func(token CancelToken) {
+		tls.Set("Hello")
+		inheritableTls.Set("World")
+		undo := EmptySnapshot().Restore()
+		assert.False(t, tls.IsSet())
+		assert.False(t, inheritableTls.IsSet())
+		tls.Set("Hello2")
+		undo()
+		assert.Equal(t, "Hello", tls.Get())
+		assert.Equal(t, "World", inheritableTls.Get())
+		//
+		EmptySnapshot().Run(func() {
+			assert.False(t, tls.IsSet())
+			assert.False(t, inheritableTls.IsSet())
+		})
+		assert.Equal(t, "Hello", tls.Get())
+	})
+	task.Get()
+	assert.Same(t, EmptySnapshot(), EmptySnapshot())
+}
@@ -0,0 +1,46 @@
+package routine
+
+// StackFormatter renders the RuntimeError with its stack trace and the cause chain into a string.
+type StackFormatter interface {
+	// Format returns the string representation of the RuntimeError.
+	Format(re RuntimeError) string
+}
+
+var (
+	// StackFormatterDotNet renders the RuntimeError in .NET style, it is the default StackFormatter.
+	StackFormatterDotNet StackFormatter = &dotNetStackFormatter{}
+
+	// StackFormatterGo renders the RuntimeError in the style of Go runtime panic output.
+	StackFormatterGo StackFormatter = &goStackFormatter{}
+
+	// StackFormatterJava renders the RuntimeError in Java style.
+	StackFormatterJava StackFormatter = &javaStackFormatter{}
+
+	// StackFormatterCompact renders the RuntimeError into a single line, only the top visible frame of each error is included.
+	StackFormatterCompact StackFormatter = &compactStackFormatter{}
+)
+
+// SetStackFormatter sets the global StackFormatter which used by the RuntimeError.Error method.
+// If the formatter is nil, the StackFormatterDotNet will be used.
+func SetStackFormatter(formatter StackFormatter) {
+	if formatter == nil {
+		formatter = StackFormatterDotNet
+	}
+	storeStackFormatter(formatter)
+}
+
+// GetStackFormatter returns the global StackFormatter.
+func GetStackFormatter() StackFormatter {
+	return loadStackFormatter()
+}
+
+// FormatRuntimeError renders the RuntimeError with the specified formatter, if the formatter is nil, the global StackFormatter will be used.
+func FormatRuntimeError(re RuntimeError, formatter StackFormatter) string {
+	if re == nil {
+		panic("re can not be nil.")
+	}
+	if formatter == nil {
+		formatter = loadStackFormatter()
+	}
+	return formatter.Format(re)
+}
@@ -0,0 +1,38 @@
+package routine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetStackFormatter(t *testing.T) {
+	defer SetStackFormatter(nil)
+	err := NewRuntimeError("Hello")
+	assert.Equal(t, StackFormatterDotNet.Format(err), err.Error())
+	//
+	SetStackFormatter(StackFormatterCompact)
+	assert.Same(t, StackFormatterCompact, GetStackFormatter())
+	assert.Equal(t, StackFormatterCompact.Format(err), err.Error())
+	//
+	SetStackFormatter(StackFormatterJava)
+	assert.Same(t, StackFormatterJava, GetStackFormatter())
+	assert.Equal(t, StackFormatterJava.Format(err), err.Error())
+	//
+	SetStackFormatter(nil)
+	assert.Same(t, StackFormatterDotNet, GetStackFormatter())
+}
+
+func TestGetStackFormatter(t *testing.T) {
+	assert.Same(t, StackFormatterDotNet, GetStackFormatter())
+}
+
+func TestFormatRuntimeError(t *testing.T) {
+	err := NewRuntimeError("Hello")
+	assert.Equal(t, StackFormatterGo.Format(err), FormatRuntimeError(err, StackFormatterGo))
+	assert.Equal(t, err.Error(), FormatRuntimeError(err, nil))
+	//
+	assert.Panics(t, func() {
+		FormatRuntimeError(nil, StackFormatterGo)
+	})
+}
@@ -0,0 +1,30 @@
+package routine
+
+// StackOptions controls how the stack trace of RuntimeError is captured and which frames are hidden.
+type StackOptions struct {
+	// MaxDepth is the max number of frames to capture, the default depth 100 will be used if it is not positive.
+	MaxDepth int
+
+	// Skip is the number of extra frames to skip, so that the helper wrappers don't appear as the top frame.
+	Skip int
+
+	// AllowPackages hides the frames whose package is not one of them or their sub packages, all packages are allowed if it is empty.
+	AllowPackages []string
+
+	// DenyPackages hides the frames whose package is one of them or their sub packages, e.g. github.com/stretchr/testify.
+	DenyPackages []string
+
+	// ShowRuntimeFrames shows the runtime internal frames which are hidden by default.
+	ShowRuntimeFrames bool
+}
+
+// SetStackOptions sets the global StackOptions which used by the RuntimeErrors created without options.
+// The capture options take effect when the error is created, and the filter options take effect when the error is rendered.
+func SetStackOptions(options StackOptions) {
+	storeStackOptions(options.clone())
+}
+
+// GetStackOptions returns the global StackOptions.
+func GetStackOptions() StackOptions {
+	return *loadStackOptions().clone()
+}
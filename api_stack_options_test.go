@@ -0,0 +1,57 @@
+package routine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetStackOptions(t *testing.T) {
+	defer SetStackOptions(StackOptions{})
+	SetStackOptions(StackOptions{MaxDepth: 1, DenyPackages: []string{"testing"}})
+	assert.Equal(t, 1, GetStackOptions().MaxDepth)
+	err := NewRuntimeError(nil)
+	assert.Equal(t, 1, len(err.StackTrace()))
+	//
+	SetStackOptions(StackOptions{DenyPackages: []string{"testing"}})
+	err2 := NewRuntimeError(nil)
+	frames := err2.Frames()
+	assert.False(t, frames[0].Hidden)
+	assert.Equal(t, "testing.tRunner", frames[1].Function)
+	assert.True(t, frames[1].Hidden)
+	//
+	SetStackOptions(StackOptions{})
+	assert.False(t, err2.Frames()[1].Hidden)
+}
+
+func TestGetStackOptions(t *testing.T) {
+	options := GetStackOptions()
+	assert.Equal(t, 0, options.MaxDepth)
+	assert.Equal(t, 0, options.Skip)
+	assert.Nil(t, options.AllowPackages)
+	assert.Nil(t, options.DenyPackages)
+	assert.False(t, options.ShowRuntimeFrames)
+}
+
+func TestSetStackOptions_Clone(t *testing.T) {
+	defer SetStackOptions(StackOptions{})
+	allowPackages := []string{"github.com/timandy/routine"}
+	denyPackages := []string{"testing"}
+	SetStackOptions(StackOptions{AllowPackages: allowPackages, DenyPackages: denyPackages})
+	allowPackages[0] = "runtime"
+	denyPackages[0] = "runtime"
+	options := GetStackOptions()
+	assert.Equal(t, []string{"github.com/timandy/routine"}, options.AllowPackages)
+	assert.Equal(t, []string{"testing"}, options.DenyPackages)
+	//
+	options.AllowPackages[0] = "runtime"
+	options.DenyPackages[0] = "runtime"
+	options = GetStackOptions()
+	assert.Equal(t, []string{"github.com/timandy/routine"}, options.AllowPackages)
+	assert.Equal(t, []string{"testing"}, options.DenyPackages)
+	//
+	err := NewRuntimeErrorWithOptions(nil, StackOptions{DenyPackages: denyPackages})
+	assert.False(t, err.Frames()[1].Hidden)
+	denyPackages[0] = "testing"
+	assert.False(t, err.Frames()[1].Hidden)
+}
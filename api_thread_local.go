@@ -1,6 +1,9 @@
 package routine
 
 // ThreadLocal provides goroutine-local variables.
+// In the default mode the values are stored along with the pprof labels of the goroutine,
+// so pprof.Do and pprof.SetGoroutineLabels hide all the values of the goroutine, use DoWithLabels and SetGoroutineLabels of this package instead.
+// The limitation does not exist in the static mode enabled by routinex.
 type ThreadLocal[T any] interface {
 	// Get returns the value in the current goroutine's local threadLocals or inheritableThreadLocals, if it was set before.
 	Get() T
@@ -10,6 +13,28 @@ type ThreadLocal[T any] interface {
 
 	// Remove delete the value from the current goroutine's local threadLocals or inheritableThreadLocals.
 	Remove()
+
+	// IsSet returns true if the value was set or initialized in the current goroutine, it will not trigger the initialization.
+	IsSet() bool
+
+	// GetOrSet returns the value if it was set before, otherwise store and return the return value of the method supplier.
+	GetOrSet(supplier Supplier[T]) T
+
+	// Update replaces the value with the return value of the method fun and returns it.
+	// The old value is the initial value if it was not set before.
+	Update(fun func(old T) T) T
+
+	// Swap replaces the value with the new value and returns the old value.
+	// The old value is the initial value if it was not set before.
+	Swap(value T) (old T)
+
+	// Name returns the name of this ThreadLocal, it is empty if this ThreadLocal is not created by the named constructors.
+	Name() string
+
+	// Close releases the index of this ThreadLocal, so that it can be reused by a ThreadLocal created later.
+	// The values left in goroutines are discarded lazily and will never be visible to the new owner of the index.
+	// The ThreadLocal should not be used after closed.
+	Close()
 }
 
 // Supplier provides a function that returns a value of type T.
@@ -18,13 +43,15 @@ type Supplier[T any] func() T
 // NewThreadLocal create and return a new ThreadLocal instance.
 // The initial value stored with the default value of type T.
 func NewThreadLocal[T any]() ThreadLocal[T] {
-	return &threadLocal[T]{index: nextThreadLocalIndex()}
+	index, version := acquireThreadLocalIndex()
+	return &threadLocal[T]{index: index, version: version}
 }
 
 // NewThreadLocalWithInitial create and return a new ThreadLocal instance.
 // The initial value stored as the return value of the method supplier.
 func NewThreadLocalWithInitial[T any](supplier Supplier[T]) ThreadLocal[T] {
-	return &threadLocal[T]{index: nextThreadLocalIndex(), supplier: supplier}
+	index, version := acquireThreadLocalIndex()
+	return &threadLocal[T]{index: index, version: version, supplier: supplier}
 }
 
 // NewInheritableThreadLocal create and return a new ThreadLocal instance.
@@ -32,7 +59,8 @@ func NewThreadLocalWithInitial[T any](supplier Supplier[T]) ThreadLocal[T] {
 // The value can be inherited to sub goroutines witch started by Go, GoWait, GoWaitResult methods.
 // The value can be captured to FutureTask which created by WrapTask, WrapWaitTask, WrapWaitResultTask methods.
 func NewInheritableThreadLocal[T any]() ThreadLocal[T] {
-	return &inheritableThreadLocal[T]{index: nextInheritableThreadLocalIndex()}
+	index, version := acquireInheritableThreadLocalIndex()
+	return &inheritableThreadLocal[T]{index: index, version: version}
 }
 
 // NewInheritableThreadLocalWithInitial create and return a new ThreadLocal instance.
@@ -40,5 +68,6 @@ func NewInheritableThreadLocal[T any]() ThreadLocal[T] {
 // The value can be inherited to sub goroutines witch started by Go, GoWait, GoWaitResult methods.
 // The value can be captured to FutureTask which created by WrapTask, WrapWaitTask, WrapWaitResultTask methods.
 func NewInheritableThreadLocalWithInitial[T any](supplier Supplier[T]) ThreadLocal[T] {
-	return &inheritableThreadLocal[T]{index: nextInheritableThreadLocalIndex(), supplier: supplier}
+	index, version := acquireInheritableThreadLocalIndex()
+	return &inheritableThreadLocal[T]{index: index, version: version, supplier: supplier}
 }
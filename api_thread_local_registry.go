@@ -0,0 +1,66 @@
+package routine
+
+// ThreadLocalInfo describes a named ThreadLocal which is registered and not closed.
+type ThreadLocalInfo struct {
+	// Name is the name of the ThreadLocal.
+	Name string
+
+	// Inheritable is true if the ThreadLocal is an inheritable one.
+	Inheritable bool
+}
+
+// ThreadLocalValue is the value of a named ThreadLocal in a goroutine.
+type ThreadLocalValue struct {
+	ThreadLocalInfo
+
+	// Value is the value stored in the goroutine.
+	Value any
+}
+
+// NewNamedThreadLocal create and return a new ThreadLocal instance with a unique name.
+// The initial value stored with the default value of type T.
+// The name is registered until the ThreadLocal is closed, so that the value can be found by DumpThreadLocals.
+func NewNamedThreadLocal[T any](name string) ThreadLocal[T] {
+	index, version := registerThreadLocal(name, false)
+	return &threadLocal[T]{name: name, index: index, version: version}
+}
+
+// NewNamedThreadLocalWithInitial create and return a new ThreadLocal instance with a unique name.
+// The initial value stored as the return value of the method supplier.
+// The name is registered until the ThreadLocal is closed, so that the value can be found by DumpThreadLocals.
+func NewNamedThreadLocalWithInitial[T any](name string, supplier Supplier[T]) ThreadLocal[T] {
+	index, version := registerThreadLocal(name, false)
+	return &threadLocal[T]{name: name, index: index, version: version, supplier: supplier}
+}
+
+// NewNamedInheritableThreadLocal create and return a new inheritable ThreadLocal instance with a unique name.
+// The initial value stored with the default value of type T.
+// The name is registered until the ThreadLocal is closed, so that the value can be found by DumpThreadLocals.
+func NewNamedInheritableThreadLocal[T any](name string) ThreadLocal[T] {
+	index, version := registerThreadLocal(name, true)
+	return &inheritableThreadLocal[T]{name: name, index: index, version: version}
+}
+
+// NewNamedInheritableThreadLocalWithInitial create and return a new inheritable ThreadLocal instance with a unique name.
+// The initial value stored as the return value of the method supplier.
+// The name is registered until the ThreadLocal is closed, so that the value can be found by DumpThreadLocals.
+func NewNamedInheritableThreadLocalWithInitial[T any](name string, supplier Supplier[T]) ThreadLocal[T] {
+	index, version := registerThreadLocal(name, true)
+	return &inheritableThreadLocal[T]{name: name, index: index, version: version, supplier: supplier}
+}
+
+// NamedThreadLocals returns all the registered named ThreadLocals, the non-inheritable ones come first and each part is sorted by registration order.
+func NamedThreadLocals() []ThreadLocalInfo {
+	registrations := threadLocalRegistrations()
+	infos := make([]ThreadLocalInfo, len(registrations))
+	for i, registration := range registrations {
+		infos[i] = registration.info()
+	}
+	return infos
+}
+
+// DumpThreadLocals returns the values of the named ThreadLocals which were set or initialized in the current goroutine.
+// Both the threadLocals and inheritableThreadLocals are dumped, the order is the same as NamedThreadLocals.
+func DumpThreadLocals() []ThreadLocalValue {
+	return dumpThreadLocals(currentThread(false))
+}
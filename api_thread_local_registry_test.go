@@ -0,0 +1,104 @@
+package routine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewNamedThreadLocal(t *testing.T) {
+	tls := NewNamedThreadLocal[string]("TestNewNamedThreadLocal")
+	defer tls.Close()
+	assert.Equal(t, "TestNewNamedThreadLocal", tls.Name())
+	assert.Equal(t, "", tls.Get())
+	tls.Set("hello")
+	assert.Equal(t, "hello", tls.Get())
+	//
+	assert.Panics(t, func() {
+		NewNamedThreadLocal[string]("")
+	})
+	assert.Panics(t, func() {
+		NewNamedInheritableThreadLocal[string]("TestNewNamedThreadLocal")
+	})
+	//
+	assert.Equal(t, "", NewThreadLocal[string]().Name())
+	assert.Equal(t, "", NewInheritableThreadLocal[string]().Name())
+}
+
+func TestNewNamedThreadLocalWithInitial(t *testing.T) {
+	tls := NewNamedThreadLocalWithInitial[string]("TestNewNamedThreadLocalWithInitial", func() string {
+		return "initial"
+	})
+	defer tls.Close()
+	assert.Equal(t, "TestNewNamedThreadLocalWithInitial", tls.Name())
+	assert.Equal(t, "initial", tls.Get())
+}
+
+func TestNewNamedInheritableThreadLocal(t *testing.T) {
+	tls := NewNamedInheritableThreadLocal[string]("TestNewNamedInheritableThreadLocal")
+	defer tls.Close()
+	assert.Equal(t, "TestNewNamedInheritableThreadLocal", tls.Name())
+	tls.Set("hello")
+	task := GoWait(func(token CancelToken) {
+		assert.Equal(t, "hello", tls.Get())
+	})
+	task.Get()
+}
+
+func TestNewNamedInheritableThreadLocalWithInitial(t *testing.T) {
+	tls := NewNamedInheritableThreadLocalWithInitial[string]("TestNewNamedInheritableThreadLocalWithInitial", func() string {
+		return "initial"
+	})
+	defer tls.Close()
+	assert.Equal(t, "TestNewNamedInheritableThreadLocalWithInitial", tls.Name())
+	assert.Equal(t, "initial", tls.Get())
+}
+
+func TestNamedThreadLocals(t *testing.T) {
+	tls := NewNamedInheritableThreadLocal[int]("TestNamedThreadLocals_Inheritable")
+	tls2 := NewNamedThreadLocal[int]("TestNamedThreadLocals")
+	infos := NamedThreadLocals()
+	assert.Contains(t, infos, ThreadLocalInfo{Name: "TestNamedThreadLocals", Inheritable: false})
+	assert.Contains(t, infos, ThreadLocalInfo{Name: "TestNamedThreadLocals_Inheritable", Inheritable: true})
+	for i := 1; i < len(infos); i++ {
+		assert.False(t, infos[i-1].Inheritable && !infos[i].Inheritable)
+	}
+	//
+	tls.Close()
+	tls2.Close()
+	infos = NamedThreadLocals()
+	assert.NotContains(t, infos, ThreadLocalInfo{Name: "TestNamedThreadLocals", Inheritable: false})
+	assert.NotContains(t, infos, ThreadLocalInfo{Name: "TestNamedThreadLocals_Inheritable", Inheritable: true})
+	//
+	tls3 := NewNamedThreadLocal[int]("TestNamedThreadLocals")
+	assert.Equal(t, "TestNamedThreadLocals", tls3.Name())
+	tls3.Close()
+}
+
+func TestDumpThreadLocals(t *testing.T) {
+	tls := NewNamedThreadLocal[string]("TestDumpThreadLocals")
+	tls2 := NewNamedInheritableThreadLocal[int]("TestDumpThreadLocals_Inheritable")
+	tls3 := NewNamedThreadLocal[string]("TestDumpThreadLocals_Unset")
+	tls4 := NewThreadLocal[string]()
+	defer tls.Close()
+	defer tls2.Close()
+	defer tls3.Close()
+	defer tls4.Close()
+	task := GoWait(func(token CancelToken) {
+		assert.Empty(t, DumpThreadLocals())
+		//
+		tls.Set("hello")
+		tls2.Set(1)
+		tls4.Set("anonymous")
+		values := DumpThreadLocals()
+		assert.Len(t, values, 2)
+		assert.Contains(t, values, ThreadLocalValue{ThreadLocalInfo: ThreadLocalInfo{Name: "TestDumpThreadLocals"}, Value: "hello"})
+		assert.Contains(t, values, ThreadLocalValue{ThreadLocalInfo: ThreadLocalInfo{Name: "TestDumpThreadLocals_Inheritable", Inheritable: true}, Value: 1})
+		//
+		tls.Remove()
+		values = DumpThreadLocals()
+		assert.Len(t, values, 1)
+		assert.Equal(t, "TestDumpThreadLocals_Inheritable", values[0].Name)
+	})
+	task.Get()
+}
@@ -706,3 +706,41 @@ func BenchmarkInheritableThreadLocalWithInitial(b *testing.B) {
 		tls.Remove()
 	}
 }
+
+func TestNewThreadLocal_Close(t *testing.T) {
+	tls := NewInheritableThreadLocal[string]()
+	tls.Set("Hello")
+	task := GoWait(func(token CancelToken) {
+		assert.Equal(t, "Hello", tls.Get())
+	})
+	task.Get()
+	tls.Close()
+	//
+	tls2 := NewInheritableThreadLocal[string]()
+	task2 := GoWait(func(token CancelToken) {
+		assert.Equal(t, "", tls2.Get())
+		tls2.Set("World")
+		assert.Equal(t, "World", tls2.Get())
+	})
+	task2.Get()
+	assert.Equal(t, "", tls2.Get())
+	tls2.Close()
+}
+
+func TestNewInheritableThreadLocal_IsSet(t *testing.T) {
+	tls := NewInheritableThreadLocal[int]()
+	tls2 := NewThreadLocal[int]()
+	tls.Set(1)
+	tls2.Set(1)
+	task := GoWait(func(token CancelToken) {
+		assert.True(t, tls.IsSet())
+		assert.False(t, tls2.IsSet())
+		assert.Equal(t, 2, tls.Update(func(old int) int {
+			return old + 1
+		}))
+	})
+	task.Get()
+	assert.Equal(t, 1, tls.Get())
+	tls.Close()
+	tls2.Close()
+}
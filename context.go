@@ -0,0 +1,65 @@
+package routine
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var contextThreadLocal = NewInheritableThreadLocal[context.Context]()
+
+type snapshotContextKey struct{}
+
+// currentContext returns the context stored in the current goroutine or nil if not set.
+func currentContext() context.Context {
+	if !contextThreadLocal.IsSet() {
+		return nil
+	}
+	return contextThreadLocal.Get()
+}
+
+// cancelWithContext cancels the task when the context of the current goroutine is done.
+func cancelWithContext[TResult any](task FutureTask[TResult]) {
+	if ctx := currentContext(); ctx != nil {
+		task.CancelWithContext(ctx)
+	}
+}
+
+// tokenContext is a context.Context derived from the parent, which is canceled when the token is done.
+type tokenContext struct {
+	context.Context
+	parent context.Context
+	token  CancelToken
+	once   sync.Once
+	err    error
+}
+
+// newTokenContext returns a tokenContext and a function to cancel it, the function should be called when the token is done.
+func newTokenContext(parent context.Context, token CancelToken) (*tokenContext, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	tokenCtx := &tokenContext{Context: ctx, parent: parent, token: token}
+	return tokenCtx, func() {
+		tokenCtx.once.Do(func() {
+			tokenCtx.err = tokenCtx.parent.Err()
+			if tokenCtx.err == nil {
+				tokenCtx.err = context.Canceled
+				if errors.Is(tokenCtx.token.Err(), ErrTimeout) {
+					tokenCtx.err = context.DeadlineExceeded
+				}
+			}
+		})
+		cancel()
+	}
+}
+
+// Err returns the error of the parent if the parent is done first, or context.DeadlineExceeded if the token timeout, otherwise context.Canceled.
+func (ctx *tokenContext) Err() error {
+	err := ctx.Context.Err()
+	if err == nil {
+		return nil
+	}
+	ctx.once.Do(func() {
+		ctx.err = err
+	})
+	return ctx.err
+}
@@ -0,0 +1,81 @@
+package routine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurrentContext(t *testing.T) {
+	task := GoWait(func(token CancelToken) {
+		assert.Nil(t, currentContext())
+		assert.False(t, contextThreadLocal.IsSet())
+		WithContext(context.TODO())
+		assert.Equal(t, context.TODO(), currentContext())
+	})
+	task.Get()
+}
+
+func TestCancelWithContext(t *testing.T) {
+	task := GoWait(func(token CancelToken) {
+		task2 := NewFutureTask[any](func(task FutureTask[any]) any { return nil })
+		cancelWithContext(task2)
+		task2.Complete(nil)
+		assert.False(t, task2.IsCanceled())
+		//
+		ctx, cancel := context.WithCancel(context.Background())
+		WithContext(ctx)
+		task3 := NewFutureTask[any](func(task FutureTask[any]) any { return nil })
+		cancelWithContext(task3)
+		cancel()
+		assert.Panics(t, func() {
+			task3.Get()
+		})
+		assert.True(t, task3.IsCanceled())
+	})
+	task.Get()
+}
+
+func TestTokenContext(t *testing.T) {
+	parent, cancel := context.WithDeadline(context.WithValue(context.Background(), contextKey{}, "Hello"), time.Now().Add(time.Hour))
+	defer cancel()
+	task := NewFutureTask[any](func(task FutureTask[any]) any { return nil })
+	ctx, cancelCtx := newTokenContext(parent, task)
+	assert.Equal(t, "Hello", ctx.Value(contextKey{}))
+	deadline, ok := ctx.Deadline()
+	assert.True(t, ok)
+	parentDeadline, _ := parent.Deadline()
+	assert.Equal(t, parentDeadline, deadline)
+	assert.Nil(t, ctx.Err())
+	task.Complete(nil)
+	cancelCtx()
+	<-ctx.Done()
+	assert.Equal(t, context.Canceled, ctx.Err())
+	assert.Nil(t, parent.Err())
+}
+
+func TestTokenContext_Parent(t *testing.T) {
+	parent, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	task := NewFutureTask[any](func(task FutureTask[any]) any { return nil })
+	ctx, cancelCtx := newTokenContext(parent, task)
+	defer cancelCtx()
+	<-ctx.Done()
+	assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+	task.Cancel()
+	assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+	assert.True(t, task.IsCanceled())
+}
+
+func TestTokenContext_Timeout(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	task := NewFutureTask[any](func(task FutureTask[any]) any { return nil })
+	ctx, cancelCtx := newTokenContext(parent, task)
+	_, _ = task.TryGetWithTimeout(time.Millisecond)
+	cancelCtx()
+	<-ctx.Done()
+	cancel()
+	assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+}
@@ -2,7 +2,9 @@ package routine
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"reflect"
 	"runtime"
 	"strconv"
@@ -17,14 +19,18 @@ const (
 	wordAt               = "at"
 	wordIn               = "in"
 	wordCreatedBy        = "created by"
+	wordStartedBy        = "started by goroutine"
 )
 
 type runtimeError struct {
 	goid       uint64
 	gopc       uintptr
+	ancestors  []Ancestor
 	message    string
 	stackTrace []uintptr
 	cause      RuntimeError
+	err        error
+	options    *StackOptions
 }
 
 func (re *runtimeError) Goid() uint64 {
@@ -35,6 +41,10 @@ func (re *runtimeError) Gopc() uintptr {
 	return re.gopc
 }
 
+func (re *runtimeError) Ancestors() []Ancestor {
+	return copyAncestors(re.ancestors)
+}
+
 func (re *runtimeError) Message() string {
 	return re.message
 }
@@ -47,34 +57,57 @@ func (re *runtimeError) Cause() RuntimeError {
 	return re.cause
 }
 
+func (re *runtimeError) Frames() []Frame {
+	return runtimeErrorFrames(re)
+}
+
 func (re *runtimeError) Error() string {
 	return runtimeErrorError(re)
 }
 
-func runtimeErrorNew(cause any) (goid uint64, gopc uintptr, msg string, stackTrace []uintptr, innerErr RuntimeError) {
-	runtimeErr, isRuntimeErr := cause.(RuntimeError)
-	if !isRuntimeErr {
-		if err, isErr := cause.(error); isErr {
-			msg = err.Error()
-		} else if cause != nil {
-			msg = fmt.Sprint(cause)
-		}
+func (re *runtimeError) Format(s fmt.State, verb rune) {
+	runtimeErrorFormat(re, s, verb)
+}
+
+func (re *runtimeError) MarshalJSON() ([]byte, error) {
+	return runtimeErrorMarshalJSON(re)
+}
+
+// Unwrap returns the inner RuntimeError or the original error which caused this error.
+func (re *runtimeError) Unwrap() error {
+	if re.cause != nil {
+		return re.cause
 	}
+	return re.err
+}
+
+func runtimeErrorNew(cause any) (goid uint64, gopc uintptr, msg string, stackTrace []uintptr, innerErr RuntimeError, originErr error) {
+	options := loadStackOptions()
+	msg, innerErr, originErr = runtimeErrorCause(cause)
+	gp := getg()
+	return gp.goid(), gp.gopc(), msg, captureStackTrace(2+options.skip(), options.depth()), innerErr, originErr
+}
+
+func runtimeErrorNewWithOptions(cause any, options *StackOptions) (goid uint64, gopc uintptr, msg string, stackTrace []uintptr, innerErr RuntimeError, originErr error) {
+	msg, innerErr, originErr = runtimeErrorCause(cause)
 	gp := getg()
-	return gp.goid(), gp.gopc(), msg, captureStackTrace(2, 100), runtimeErr
+	return gp.goid(), gp.gopc(), msg, captureStackTrace(2+options.skip(), options.depth()), innerErr, originErr
 }
 
-func runtimeErrorNewWithMessage(message string) (goid uint64, gopc uintptr, msg string, stackTrace []uintptr, innerErr RuntimeError) {
+func runtimeErrorNewWithMessage(message string) (goid uint64, gopc uintptr, msg string, stackTrace []uintptr, innerErr RuntimeError, originErr error) {
+	options := loadStackOptions()
 	gp := getg()
-	return gp.goid(), gp.gopc(), message, captureStackTrace(2, 100), nil
+	return gp.goid(), gp.gopc(), message, captureStackTrace(2+options.skip(), options.depth()), nil, nil
 }
 
-func runtimeErrorNewWithMessageCause(message string, cause any) (goid uint64, gopc uintptr, msg string, stackTrace []uintptr, innerErr RuntimeError) {
+func runtimeErrorNewWithMessageCause(message string, cause any) (goid uint64, gopc uintptr, msg string, stackTrace []uintptr, innerErr RuntimeError, originErr error) {
+	options := loadStackOptions()
 	runtimeErr, isRuntimeErr := cause.(RuntimeError)
 	if !isRuntimeErr {
 		causeMsg := ""
 		if err, isErr := cause.(error); isErr {
 			causeMsg = err.Error()
+			originErr = err
 		} else if cause != nil {
 			causeMsg = fmt.Sprint(cause)
 		}
@@ -85,14 +118,41 @@ func runtimeErrorNewWithMessageCause(message string, cause any) (goid uint64, go
 		}
 	}
 	gp := getg()
-	return gp.goid(), gp.gopc(), message, captureStackTrace(2, 100), runtimeErr
+	return gp.goid(), gp.gopc(), message, captureStackTrace(2+options.skip(), options.depth()), runtimeErr, originErr
+}
+
+func runtimeErrorCause(cause any) (msg string, innerErr RuntimeError, originErr error) {
+	runtimeErr, isRuntimeErr := cause.(RuntimeError)
+	if !isRuntimeErr {
+		if err, isErr := cause.(error); isErr {
+			msg = err.Error()
+			originErr = err
+		} else if cause != nil {
+			msg = fmt.Sprint(cause)
+		}
+	}
+	return msg, runtimeErr, originErr
 }
 
 func runtimeErrorError(re RuntimeError) string {
-	builder := &bytes.Buffer{}
-	runtimeErrorPrintStackTrace(re, builder)
-	runtimeErrorPrintCreatedBy(re, builder)
-	return builder.String()
+	return loadStackFormatter().Format(re)
+}
+
+func runtimeErrorFormat(re RuntimeError, s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			_, _ = io.WriteString(s, re.Error())
+			return
+		}
+		_, _ = io.WriteString(s, re.Message())
+	case 's':
+		_, _ = io.WriteString(s, re.Message())
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", re.Message())
+	default:
+		_, _ = fmt.Fprintf(s, "%%!%c(%s)", verb, runtimeErrorTypeName(re))
+	}
 }
 
 func runtimeErrorPrintStackTrace(re RuntimeError, builder *bytes.Buffer) {
@@ -111,52 +171,36 @@ func runtimeErrorPrintStackTrace(re RuntimeError, builder *bytes.Buffer) {
 		builder.WriteString("   ")
 		builder.WriteString(endOfInnerErrorStack)
 	}
-	stackTrace := re.StackTrace()
-	if stackTrace != nil {
-		savePoint := builder.Len()
-		skippedPanic := false
-		frames := runtime.CallersFrames(stackTrace)
-		for {
-			frame, more := frames.Next()
-			if showFrame(frame.Function) {
-				builder.WriteString(newLine)
-				builder.WriteString("   ")
-				builder.WriteString(wordAt)
-				builder.WriteString(" ")
-				builder.WriteString(frame.Function)
-				builder.WriteString("() ")
-				builder.WriteString(wordIn)
-				builder.WriteString(" ")
-				builder.WriteString(frame.File)
-				builder.WriteString(":")
-				builder.WriteString(strconv.Itoa(frame.Line))
-			} else if skipFrame(frame.Function, skippedPanic) {
-				builder.Truncate(savePoint)
-				skippedPanic = true
-			}
-			if !more {
-				break
-			}
+	for _, frame := range runtimeErrorFrames(re) {
+		if !frame.Hidden {
+			builder.WriteString(newLine)
+			runtimeErrorPrintFrame(wordAt, frame, builder)
 		}
 	}
 }
 
 func runtimeErrorPrintCreatedBy(re RuntimeError, builder *bytes.Buffer) {
-	goid := re.Goid()
-	if goid == 1 {
-		return
-	}
-	pc := re.Gopc()
-	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
-	if frame.Func == nil {
+	frame, ok := runtimeErrorCreatedBy(re)
+	ancestors := re.Ancestors()
+	if !ok && len(ancestors) == 0 {
 		return
 	}
 	builder.WriteString(newLine)
 	builder.WriteString("   ")
 	builder.WriteString(endOfErrorStack)
-	builder.WriteString(newLine)
+	if ok {
+		builder.WriteString(newLine)
+		runtimeErrorPrintFrame(wordCreatedBy, frame, builder)
+	}
+	for _, ancestor := range ancestors {
+		builder.WriteString(newLine)
+		runtimeErrorPrintFrame(wordStartedBy+" "+strconv.FormatUint(ancestor.Goid, 10)+" "+wordAt, ancestor.Frame(), builder)
+	}
+}
+
+func runtimeErrorPrintFrame(word string, frame Frame, builder *bytes.Buffer) {
 	builder.WriteString("   ")
-	builder.WriteString(wordCreatedBy)
+	builder.WriteString(word)
 	builder.WriteString(" ")
 	builder.WriteString(frame.Function)
 	builder.WriteString("() ")
@@ -167,6 +211,98 @@ func runtimeErrorPrintCreatedBy(re RuntimeError, builder *bytes.Buffer) {
 	builder.WriteString(strconv.Itoa(frame.Line))
 }
 
+// runtimeErrorFrames resolves the stack trace, the runtime frames, the frames before the panic and the frames filtered by the StackOptions are hidden.
+func runtimeErrorFrames(re RuntimeError) []Frame {
+	stackTrace := re.StackTrace()
+	if stackTrace == nil {
+		return nil
+	}
+	options := runtimeErrorOptions(re)
+	result := make([]Frame, 0, len(stackTrace))
+	skippedPanic := false
+	frames := runtime.CallersFrames(stackTrace)
+	for {
+		frame, more := frames.Next()
+		show := showFrame(frame.Function)
+		if !show && skipFrame(frame.Function, skippedPanic) {
+			for i := range result {
+				result[i].Hidden = true
+			}
+			skippedPanic = true
+		}
+		if !show && options.ShowRuntimeFrames {
+			show = len(frame.Function) > 0
+		}
+		item := newFrame(frame, !show)
+		if show && !options.allow(item.Package) {
+			item.Hidden = true
+		}
+		result = append(result, item)
+		if !more {
+			break
+		}
+	}
+	return result
+}
+
+// runtimeErrorOptions returns the StackOptions specified when the error was created, or the global StackOptions.
+func runtimeErrorOptions(re RuntimeError) *StackOptions {
+	if err, ok := re.(*runtimeError); ok && err.options != nil {
+		return err.options
+	}
+	return loadStackOptions()
+}
+
+// runtimeErrorCreatedBy resolves the go statement which created the goroutine, returns false for the main goroutine or unknown pc.
+func runtimeErrorCreatedBy(re RuntimeError) (Frame, bool) {
+	if re.Goid() == 1 {
+		return Frame{}, false
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{re.Gopc()}).Next()
+	if frame.Func == nil {
+		return Frame{}, false
+	}
+	return newFrame(frame, false), true
+}
+
+type runtimeErrorJSON struct {
+	Type      string            `json:"type"`
+	Message   string            `json:"message"`
+	Goid      uint64            `json:"goid"`
+	Frames    []Frame           `json:"frames,omitempty"`
+	CreatedBy *Frame            `json:"createdBy,omitempty"`
+	Ancestors []ancestorJSON    `json:"ancestors,omitempty"`
+	Cause     *runtimeErrorJSON `json:"cause,omitempty"`
+}
+
+type ancestorJSON struct {
+	Goid  uint64 `json:"goid"`
+	Frame Frame  `json:"frame"`
+}
+
+func runtimeErrorMarshalJSON(re RuntimeError) ([]byte, error) {
+	return json.Marshal(newRuntimeErrorJSON(re))
+}
+
+func newRuntimeErrorJSON(re RuntimeError) *runtimeErrorJSON {
+	value := &runtimeErrorJSON{
+		Type:    runtimeErrorTypeName(re),
+		Message: re.Message(),
+		Goid:    re.Goid(),
+		Frames:  runtimeErrorFrames(re),
+	}
+	if frame, ok := runtimeErrorCreatedBy(re); ok {
+		value.CreatedBy = &frame
+	}
+	for _, ancestor := range re.Ancestors() {
+		value.Ancestors = append(value.Ancestors, ancestorJSON{Goid: ancestor.Goid, Frame: ancestor.Frame()})
+	}
+	if cause := re.Cause(); cause != nil {
+		value.Cause = newRuntimeErrorJSON(cause)
+	}
+	return value
+}
+
 func runtimeErrorTypeName(re RuntimeError) string {
 	typeName := []rune(reflect.TypeOf(re).Elem().Name())
 	typeName[0] = unicode.ToUpper(typeName[0])
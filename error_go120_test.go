@@ -0,0 +1,26 @@
+//go:build go1.20
+
+package routine
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuntimeError_Unwrap_MultiCause(t *testing.T) {
+	cause := errors.New("cause")
+	cause2 := NewRuntimeError(ErrTimeout)
+	err := NewRuntimeError(errors.Join(cause, cause2))
+	assert.True(t, errors.Is(err, cause))
+	assert.True(t, errors.Is(err, cause2))
+	assert.True(t, errors.Is(err, ErrTimeout))
+	assert.False(t, errors.Is(err, ErrCanceled))
+	//
+	var target RuntimeError
+	joined := errors.Join(cause, NewRuntimeErrorWithMessageCause("outer", cause2))
+	assert.True(t, errors.As(joined, &target))
+	assert.Equal(t, "outer", target.Message())
+	assert.True(t, errors.Is(joined, ErrTimeout))
+}
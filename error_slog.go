@@ -0,0 +1,32 @@
+//go:build go1.21
+
+package routine
+
+import "log/slog"
+
+// LogValue implements slog.LogValuer, the type, message, goid, visible stack frames and the cause chain are emitted as a group.
+func (re *runtimeError) LogValue() slog.Value {
+	return runtimeErrorLogValue(re)
+}
+
+func runtimeErrorLogValue(re RuntimeError) slog.Value {
+	frames := make([]Frame, 0)
+	for _, frame := range re.Frames() {
+		if !frame.Hidden {
+			frames = append(frames, frame)
+		}
+	}
+	attrs := []slog.Attr{
+		slog.String("type", runtimeErrorTypeName(re)),
+		slog.String("message", re.Message()),
+		slog.Uint64("goid", re.Goid()),
+		slog.Any("frames", frames),
+	}
+	if frame, ok := runtimeErrorCreatedBy(re); ok {
+		attrs = append(attrs, slog.Any("createdBy", frame))
+	}
+	if cause := re.Cause(); cause != nil {
+		attrs = append(attrs, slog.Attr{Key: "cause", Value: runtimeErrorLogValue(cause)})
+	}
+	return slog.GroupValue(attrs...)
+}
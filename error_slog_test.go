@@ -0,0 +1,39 @@
+//go:build go1.21
+
+package routine
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuntimeError_LogValue(t *testing.T) {
+	err := NewRuntimeErrorWithMessageCause("outer", NewRuntimeError("inner"))
+	buffer := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buffer, nil))
+	logger.Error("failed", "error", err)
+	var value map[string]any
+	assert.Nil(t, json.Unmarshal(buffer.Bytes(), &value))
+	errValue := value["error"].(map[string]any)
+	assert.Equal(t, "RuntimeError", errValue["type"])
+	assert.Equal(t, "outer", errValue["message"])
+	assert.Equal(t, float64(err.Goid()), errValue["goid"])
+	frames := errValue["frames"].([]any)
+	assert.Equal(t, "github.com/timandy/routine.TestRuntimeError_LogValue", frames[0].(map[string]any)["function"])
+	for _, frame := range frames {
+		assert.Equal(t, false, frame.(map[string]any)["hidden"])
+	}
+	assert.Equal(t, "testing.(*T).Run", errValue["createdBy"].(map[string]any)["function"])
+	cause := errValue["cause"].(map[string]any)
+	assert.Equal(t, "inner", cause["message"])
+	assert.NotContains(t, cause, "cause")
+	//
+	err2 := &runtimeError{goid: 1, message: "main"}
+	value2 := err2.LogValue()
+	assert.Equal(t, slog.KindGroup, value2.Kind())
+	assert.Equal(t, 4, len(value2.Group()))
+}
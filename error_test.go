@@ -1,9 +1,14 @@
 package routine
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -63,11 +68,11 @@ func TestRuntimeError_Panic_Panic(t *testing.T) {
 		//
 		line = lines[1]
 		assert.True(t, strings.HasPrefix(line, "   at github.com/timandy/routine.TestRuntimeError_Panic_Panic."))
-		assert.True(t, strings.HasSuffix(line, "error_test.go:74"))
+		assert.True(t, strings.HasSuffix(line, "error_test.go:79"))
 		//
 		line = lines[2]
 		assert.True(t, strings.HasPrefix(line, "   at github.com/timandy/routine.TestRuntimeError_Panic_Panic()"))
-		assert.True(t, strings.HasSuffix(line, "error_test.go:77"))
+		assert.True(t, strings.HasSuffix(line, "error_test.go:82"))
 	}()
 	defer func() {
 		if cause := recover(); cause != nil {
@@ -101,7 +106,7 @@ func TestRuntimeError_Error_EmptyMessage_NilError(t *testing.T) {
 	//
 	line = lines[1]
 	assert.True(t, strings.HasPrefix(line, "   at github.com/timandy/routine.TestRuntimeError_Error_EmptyMessage_NilError() in "))
-	assert.True(t, strings.HasSuffix(line, "error_test.go:95"))
+	assert.True(t, strings.HasSuffix(line, "error_test.go:100"))
 	//
 	line = lines[2]
 	assert.True(t, strings.HasPrefix(line, "   at testing.tRunner() in "))
@@ -127,7 +132,7 @@ func TestRuntimeError_Error_EmptyMessage_NormalError(t *testing.T) {
 	//
 	line = lines[2]
 	assert.True(t, strings.HasPrefix(line, "   at github.com/timandy/routine.TestRuntimeError_Error_EmptyMessage_NormalError() in "))
-	assert.True(t, strings.HasSuffix(line, "error_test.go:117"))
+	assert.True(t, strings.HasSuffix(line, "error_test.go:122"))
 	//
 	line = lines[3]
 	assert.True(t, strings.HasPrefix(line, "   at testing.tRunner() in "))
@@ -137,7 +142,7 @@ func TestRuntimeError_Error_EmptyMessage_NormalError(t *testing.T) {
 	//
 	line = lines[5]
 	assert.True(t, strings.HasPrefix(line, "   at github.com/timandy/routine.TestRuntimeError_Error_EmptyMessage_NormalError() in "))
-	assert.True(t, strings.HasSuffix(line, "error_test.go:118"))
+	assert.True(t, strings.HasSuffix(line, "error_test.go:123"))
 	//
 	line = lines[6]
 	assert.True(t, strings.HasPrefix(line, "   at testing.tRunner() in "))
@@ -159,7 +164,7 @@ func TestRuntimeError_Error_NormalMessage_NilError(t *testing.T) {
 	//
 	line = lines[1]
 	assert.True(t, strings.HasPrefix(line, "   at github.com/timandy/routine.TestRuntimeError_Error_NormalMessage_NilError() in "))
-	assert.True(t, strings.HasSuffix(line, "error_test.go:153"))
+	assert.True(t, strings.HasSuffix(line, "error_test.go:158"))
 	//
 	line = lines[2]
 	assert.True(t, strings.HasPrefix(line, "   at testing.tRunner() in "))
@@ -185,7 +190,7 @@ func TestRuntimeError_Error_NormalMessage_NormalError(t *testing.T) {
 	//
 	line = lines[2]
 	assert.True(t, strings.HasPrefix(line, "   at github.com/timandy/routine.TestRuntimeError_Error_NormalMessage_NormalError() in "))
-	assert.True(t, strings.HasSuffix(line, "error_test.go:175"))
+	assert.True(t, strings.HasSuffix(line, "error_test.go:180"))
 	//
 	line = lines[3]
 	assert.True(t, strings.HasPrefix(line, "   at testing.tRunner() in "))
@@ -195,7 +200,7 @@ func TestRuntimeError_Error_NormalMessage_NormalError(t *testing.T) {
 	//
 	line = lines[5]
 	assert.True(t, strings.HasPrefix(line, "   at github.com/timandy/routine.TestRuntimeError_Error_NormalMessage_NormalError() in "))
-	assert.True(t, strings.HasSuffix(line, "error_test.go:176"))
+	assert.True(t, strings.HasSuffix(line, "error_test.go:181"))
 	//
 	line = lines[6]
 	assert.True(t, strings.HasPrefix(line, "   at testing.tRunner() in "))
@@ -242,7 +247,7 @@ func TestRuntimeError_Error_MainGoid(t *testing.T) {
 	//
 	line = lines[1]
 	assert.True(t, strings.HasPrefix(line, "   at github.com/timandy/routine.TestRuntimeError_Error_MainGoid() in "))
-	assert.True(t, strings.HasSuffix(line, "error_test.go:235"))
+	assert.True(t, strings.HasSuffix(line, "error_test.go:240"))
 	//
 	line = lines[2]
 	assert.True(t, strings.HasPrefix(line, "   at testing.tRunner() in "))
@@ -259,7 +264,7 @@ func TestRuntimeError_Error_ZeroGopc(t *testing.T) {
 	//
 	line = lines[1]
 	assert.True(t, strings.HasPrefix(line, "   at github.com/timandy/routine.TestRuntimeError_Error_ZeroGopc() in "))
-	assert.True(t, strings.HasSuffix(line, "error_test.go:252"))
+	assert.True(t, strings.HasSuffix(line, "error_test.go:257"))
 	//
 	line = lines[2]
 	assert.True(t, strings.HasPrefix(line, "   at testing.tRunner() in "))
@@ -326,11 +331,11 @@ func TestArgumentNilError_Panic_Panic(t *testing.T) {
 		//
 		line = lines[2]
 		assert.True(t, strings.HasPrefix(line, "   at github.com/timandy/routine.TestArgumentNilError_Panic_Panic."))
-		assert.True(t, strings.HasSuffix(line, "error_test.go:337"))
+		assert.True(t, strings.HasSuffix(line, "error_test.go:342"))
 		//
 		line = lines[3]
 		assert.True(t, strings.HasPrefix(line, "   at github.com/timandy/routine.TestArgumentNilError_Panic_Panic()"))
-		assert.True(t, strings.HasSuffix(line, "error_test.go:341"))
+		assert.True(t, strings.HasSuffix(line, "error_test.go:346"))
 	}()
 	defer func() {
 		if cause := recover(); cause != nil {
@@ -372,7 +377,7 @@ func TestArgumentNilError_Error(t *testing.T) {
 	//
 	line = lines[3]
 	assert.True(t, strings.HasPrefix(line, "   at github.com/timandy/routine.TestArgumentNilError_Error() in "))
-	assert.True(t, strings.HasSuffix(line, "error_test.go:359"))
+	assert.True(t, strings.HasSuffix(line, "error_test.go:364"))
 	//
 	line = lines[4]
 	assert.True(t, strings.HasPrefix(line, "   at testing.tRunner() in "))
@@ -382,7 +387,7 @@ func TestArgumentNilError_Error(t *testing.T) {
 	//
 	line = lines[6]
 	assert.True(t, strings.HasPrefix(line, "   at github.com/timandy/routine.TestArgumentNilError_Error() in "))
-	assert.True(t, strings.HasSuffix(line, "error_test.go:360"))
+	assert.True(t, strings.HasSuffix(line, "error_test.go:365"))
 	//
 	line = lines[7]
 	assert.True(t, strings.HasPrefix(line, "   at testing.tRunner() in "))
@@ -405,6 +410,7 @@ func TestArgumentNilError_ParamName(t *testing.T) {
 type ArgumentNilError struct {
 	goid       uint64
 	gopc       uintptr
+	ancestors  []Ancestor
 	message    string
 	stackTrace []uintptr
 	cause      RuntimeError
@@ -419,6 +425,10 @@ func (ae *ArgumentNilError) Gopc() uintptr {
 	return ae.gopc
 }
 
+func (ae *ArgumentNilError) Ancestors() []Ancestor {
+	return ae.ancestors
+}
+
 func (ae *ArgumentNilError) Message() string {
 	builder := &strings.Builder{}
 	if len(ae.message) == 0 {
@@ -443,15 +453,195 @@ func (ae *ArgumentNilError) Cause() RuntimeError {
 	return ae.cause
 }
 
+func (ae *ArgumentNilError) Unwrap() error {
+	if ae.cause != nil {
+		return ae.cause
+	}
+	return nil
+}
+
+func (ae *ArgumentNilError) Frames() []Frame {
+	return runtimeErrorFrames(ae)
+}
+
 func (ae *ArgumentNilError) Error() string {
 	return runtimeErrorError(ae)
 }
 
+func (ae *ArgumentNilError) Format(s fmt.State, verb rune) {
+	runtimeErrorFormat(ae, s, verb)
+}
+
+func (ae *ArgumentNilError) MarshalJSON() ([]byte, error) {
+	return runtimeErrorMarshalJSON(ae)
+}
+
 func (ae *ArgumentNilError) ParamName() string {
 	return ae.paramName
 }
 
 func NewArgumentNilError(paramName string, cause any) *ArgumentNilError {
-	goid, gopc, msg, stackTrace, innerErr := runtimeErrorNew(cause)
-	return &ArgumentNilError{goid: goid, gopc: gopc, message: msg, paramName: paramName, stackTrace: stackTrace, cause: innerErr}
+	goid, gopc, msg, stackTrace, innerErr, _ := runtimeErrorNew(cause)
+	return &ArgumentNilError{goid: goid, gopc: gopc, ancestors: currentAncestors(), message: msg, paramName: paramName, stackTrace: stackTrace, cause: innerErr}
+}
+
+func TestRuntimeError_Unwrap(t *testing.T) {
+	err := NewRuntimeError(nil)
+	assert.Nil(t, errors.Unwrap(err))
+	//
+	cause := errors.New("error")
+	err2 := NewRuntimeError(cause)
+	assert.Same(t, cause, errors.Unwrap(err2))
+	assert.True(t, errors.Is(err2, cause))
+	//
+	err3 := NewRuntimeErrorWithMessageCause("message", err2)
+	assert.Same(t, err2, errors.Unwrap(err3))
+	assert.True(t, errors.Is(err3, cause))
+	//
+	err4 := NewRuntimeErrorWithMessageCause("message", cause)
+	assert.Equal(t, "message - error", err4.Message())
+	assert.Same(t, cause, errors.Unwrap(err4))
+	//
+	err5 := NewRuntimeErrorWithMessage("message")
+	assert.Nil(t, errors.Unwrap(err5))
+}
+
+func TestRuntimeError_Frames(t *testing.T) {
+	err, line := NewRuntimeError(nil), currentLine()
+	frames := err.Frames()
+	assert.Greater(t, len(frames), 1)
+	assert.Equal(t, "github.com/timandy/routine.TestRuntimeError_Frames", frames[0].Function)
+	assert.Equal(t, "github.com/timandy/routine", frames[0].Package)
+	assert.True(t, strings.HasSuffix(frames[0].File, "error_test.go"))
+	assert.Equal(t, line, frames[0].Line)
+	assert.False(t, frames[0].Hidden)
+	assert.Equal(t, "testing.tRunner", frames[1].Function)
+	assert.Equal(t, "testing", frames[1].Package)
+	assert.False(t, frames[1].Hidden)
+	assert.True(t, frames[len(frames)-1].Hidden)
+	//
+	err2 := &runtimeError{}
+	assert.Nil(t, err2.Frames())
+}
+
+func TestRuntimeError_Frames_Panic(t *testing.T) {
+	defer func() {
+		err := NewRuntimeError(recover())
+		frames := err.Frames()
+		visible := make([]Frame, 0, len(frames))
+		for _, frame := range frames {
+			if !frame.Hidden {
+				visible = append(visible, frame)
+			}
+		}
+		assert.Equal(t, "github.com/timandy/routine.TestRuntimeError_Frames_Panic", visible[0].Function)
+		assert.True(t, frames[0].Hidden)
+		assert.Equal(t, 3+len(visible), len(strings.Split(err.Error(), newLine)))
+	}()
+	panic(1)
+}
+
+func TestRuntimeError_MarshalJSON(t *testing.T) {
+	cause := NewRuntimeError("inner")
+	err, line := NewRuntimeErrorWithMessageCause("outer", cause), currentLine()
+	data, marshalErr := json.Marshal(err)
+	assert.Nil(t, marshalErr)
+	var value map[string]any
+	assert.Nil(t, json.Unmarshal(data, &value))
+	assert.Equal(t, "RuntimeError", value["type"])
+	assert.Equal(t, "outer", value["message"])
+	assert.Equal(t, float64(err.Goid()), value["goid"])
+	frames := value["frames"].([]any)
+	frame := frames[0].(map[string]any)
+	assert.Equal(t, "github.com/timandy/routine.TestRuntimeError_MarshalJSON", frame["function"])
+	assert.Equal(t, "github.com/timandy/routine", frame["package"])
+	assert.Equal(t, float64(line), frame["line"])
+	assert.Equal(t, false, frame["hidden"])
+	createdBy := value["createdBy"].(map[string]any)
+	assert.Equal(t, "testing.(*T).Run", createdBy["function"])
+	inner := value["cause"].(map[string]any)
+	assert.Equal(t, "inner", inner["message"])
+	assert.NotNil(t, inner["createdBy"])
+	assert.Nil(t, inner["cause"])
+	//
+	err2 := &runtimeError{goid: 1, message: "main"}
+	data2, marshalErr2 := err2.MarshalJSON()
+	assert.Nil(t, marshalErr2)
+	assert.Equal(t, `{"type":"RuntimeError","message":"main","goid":1}`, string(data2))
+	//
+	err3 := NewArgumentNilError("number", nil)
+	data3, marshalErr3 := json.Marshal(err3)
+	assert.Nil(t, marshalErr3)
+	assert.True(t, strings.HasPrefix(string(data3), `{"type":"ArgumentNilError","message":"Value cannot be null.\nParameter name: number.",`))
+}
+
+func TestRuntimeError_As(t *testing.T) {
+	cause := &timeoutError{timeout: time.Second}
+	err := NewRuntimeErrorWithMessageCause("outer", NewRuntimeError(cause))
+	var target *timeoutError
+	assert.True(t, errors.As(err, &target))
+	assert.Same(t, cause, target)
+	//
+	var argErr *ArgumentNilError
+	err2 := NewRuntimeError(NewArgumentNilError("number", cause))
+	assert.True(t, errors.As(err2, &argErr))
+	assert.Equal(t, "number", argErr.ParamName())
+	assert.False(t, errors.As(err2, &target))
+	assert.Nil(t, errors.Unwrap(argErr))
+}
+
+func TestRuntimeError_Format(t *testing.T) {
+	cause := NewRuntimeError("inner")
+	err := NewRuntimeErrorWithMessageCause("outer", cause)
+	assert.Equal(t, "outer", fmt.Sprintf("%v", err))
+	assert.Equal(t, "outer", fmt.Sprintf("%s", err))
+	assert.Equal(t, `"outer"`, fmt.Sprintf("%q", err))
+	assert.Equal(t, err.Error(), fmt.Sprintf("%+v", err))
+	assert.Equal(t, "%!d(RuntimeError)", fmt.Sprintf("%d", err))
+	assert.Equal(t, "error: outer", fmt.Errorf("error: %w", err).Error())
+	//
+	err2 := NewArgumentNilError("number", nil)
+	assert.Equal(t, "Value cannot be null.\nParameter name: number.", fmt.Sprintf("%v", err2))
+	assert.Equal(t, err2.Error(), fmt.Sprintf("%+v", err2))
+}
+
+func TestRuntimeError_Ancestors(t *testing.T) {
+	err := NewRuntimeError(nil)
+	assert.Nil(t, err.Ancestors())
+	assert.False(t, strings.Contains(err.Error(), wordStartedBy))
+	//
+	goid := Goid()
+	task := GoWaitResult(func(token CancelToken) RuntimeError {
+		return NewRuntimeError("Hello")
+	})
+	err2 := task.Get()
+	ancestors := err2.Ancestors()
+	assert.Equal(t, 1, len(ancestors))
+	assert.Equal(t, goid, ancestors[0].Goid)
+	lines := strings.Split(err2.Error(), newLine)
+	line := lines[len(lines)-1]
+	assert.True(t, strings.HasPrefix(line, "   started by goroutine "+strconv.FormatUint(goid, 10)+" at github.com/timandy/routine.TestRuntimeError_Ancestors() in "))
+	assert.True(t, strings.HasSuffix(line, "error_test.go:614"))
+	//
+	data, marshalErr := json.Marshal(err2)
+	assert.Nil(t, marshalErr)
+	var value map[string]any
+	assert.Nil(t, json.Unmarshal(data, &value))
+	ancestor := value["ancestors"].([]any)[0].(map[string]any)
+	assert.Equal(t, float64(goid), ancestor["goid"])
+	assert.Equal(t, "github.com/timandy/routine.TestRuntimeError_Ancestors", ancestor["frame"].(map[string]any)["function"])
+	//
+	err3 := &runtimeError{goid: 1, ancestors: ancestors}
+	lines3 := strings.Split(err3.Error(), newLine)
+	assert.Equal(t, 3, len(lines3))
+	assert.Equal(t, "   --- End of error stack trace ---", lines3[1])
+	assert.Equal(t, line, lines3[2])
+	//
+	ancestors[0].Goid = 0
+	assert.Equal(t, goid, err2.Ancestors()[0].Goid)
+}
+
+func currentLine() int {
+	_, _, line, _ := runtime.Caller(1)
+	return line
 }
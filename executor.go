@@ -0,0 +1,191 @@
+package routine
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type offerResult int
+
+const (
+	offerAccepted offerResult = iota
+	offerFull
+	offerShutdown
+)
+
+type fixedExecutor struct {
+	policy     RejectPolicy
+	queue      chan Task
+	quit       chan struct{}
+	terminated chan struct{}
+	shutdown   int32
+	stopped    int32
+	mutex      sync.RWMutex
+	workers    sync.WaitGroup
+	running    []Task
+	runningMu  sync.Mutex
+}
+
+func newFixedExecutor(workers int, queueSize int, policy RejectPolicy) *fixedExecutor {
+	e := &fixedExecutor{
+		policy:     policy,
+		queue:      make(chan Task, queueSize),
+		quit:       make(chan struct{}),
+		terminated: make(chan struct{}),
+		running:    make([]Task, workers),
+	}
+	e.workers.Add(workers)
+	for i := 0; i < workers; i++ {
+		go e.work(i)
+	}
+	go func() {
+		e.workers.Wait()
+		close(e.terminated)
+	}()
+	return e
+}
+
+func (e *fixedExecutor) Execute(task Task) {
+	if task == nil {
+		panic("task can not be nil.")
+	}
+	switch e.offer(task) {
+	case offerAccepted:
+	case offerFull:
+		if e.policy == RejectPolicyCallerRuns {
+			task.Run()
+			return
+		}
+		task.Fail(ErrRejected)
+	case offerShutdown:
+		task.Fail(ErrRejected)
+	}
+}
+
+func (e *fixedExecutor) Submit(fun Runnable) FutureTask[any] {
+	if fun == nil {
+		panic("fun can not be nil.")
+	}
+	task := wrapWaitTask(func(token CancelToken) {
+		fun()
+	}, 1)
+	e.Execute(task)
+	return task
+}
+
+func (e *fixedExecutor) Shutdown() {
+	if !atomic.CompareAndSwapInt32(&e.shutdown, 0, 1) {
+		return
+	}
+	// release the blocked callers
+	close(e.quit)
+	// wait for the callers which are sending
+	e.mutex.Lock()
+	close(e.queue)
+	e.mutex.Unlock()
+}
+
+func (e *fixedExecutor) ShutdownNow() []Task {
+	// the workers must not run the tasks taken after this point
+	e.runningMu.Lock()
+	atomic.StoreInt32(&e.stopped, 1)
+	e.runningMu.Unlock()
+	e.Shutdown()
+	var tasks []Task
+	for task := range e.queue {
+		tasks = append(tasks, task)
+	}
+	e.runningMu.Lock()
+	running := make([]Task, 0, len(e.running))
+	for _, task := range e.running {
+		if task != nil {
+			running = append(running, task)
+		}
+	}
+	e.runningMu.Unlock()
+	for _, task := range running {
+		task.Cancel()
+	}
+	return tasks
+}
+
+func (e *fixedExecutor) IsShutdown() bool {
+	return atomic.LoadInt32(&e.shutdown) == 1
+}
+
+func (e *fixedExecutor) IsTerminated() bool {
+	select {
+	case <-e.terminated:
+		return true
+	default:
+		return false
+	}
+}
+
+func (e *fixedExecutor) AwaitTermination(timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-e.terminated:
+		return true
+	case <-timer.C:
+		return e.IsTerminated()
+	}
+}
+
+func (e *fixedExecutor) offer(task Task) offerResult {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	if e.IsShutdown() {
+		return offerShutdown
+	}
+	select {
+	case e.queue <- task:
+		return offerAccepted
+	default:
+	}
+	if e.policy != RejectPolicyBlock {
+		return offerFull
+	}
+	select {
+	case e.queue <- task:
+		return offerAccepted
+	case <-e.quit:
+		return offerShutdown
+	}
+}
+
+func (e *fixedExecutor) work(id int) {
+	defer e.workers.Done()
+	for task := range e.queue {
+		if !e.setRunning(id, task) {
+			// taken from the queue after ShutdownNow, it will not be returned to the caller
+			task.Cancel()
+			continue
+		}
+		e.run(task)
+		e.setRunning(id, nil)
+	}
+}
+
+// run runs the task and fails it with the panic raised by the Run method, so the worker keeps alive.
+func (e *fixedExecutor) run(task Task) {
+	defer func() {
+		if cause := recover(); cause != nil {
+			task.Fail(NewRuntimeError(cause))
+		}
+	}()
+	task.Run()
+}
+
+// setRunning records the task running by the worker, returns false if the executor has been stopped by ShutdownNow.
+func (e *fixedExecutor) setRunning(id int, task Task) bool {
+	e.runningMu.Lock()
+	defer e.runningMu.Unlock()
+	if task != nil && atomic.LoadInt32(&e.stopped) == 1 {
+		return false
+	}
+	e.running[id] = task
+	return true
+}
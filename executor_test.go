@@ -0,0 +1,105 @@
+package routine
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFixedExecutor_Offer(t *testing.T) {
+	executor := newFixedExecutor(1, 1, RejectPolicyFail)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	task := WrapWaitTask(func(token CancelToken) {
+		close(started)
+		<-release
+	})
+	assert.Equal(t, offerAccepted, executor.offer(task))
+	<-started
+	assert.Equal(t, offerAccepted, executor.offer(WrapWaitTask(func(token CancelToken) {})))
+	assert.Equal(t, offerFull, executor.offer(WrapWaitTask(func(token CancelToken) {})))
+	close(release)
+	executor.Shutdown()
+	assert.Equal(t, offerShutdown, executor.offer(WrapWaitTask(func(token CancelToken) {})))
+	assert.True(t, executor.AwaitTermination(time.Second))
+}
+
+func TestFixedExecutor_Work(t *testing.T) {
+	tls := NewThreadLocal[string]()
+	executor := newFixedExecutor(1, 0, RejectPolicyBlock)
+	task := WrapWaitTask(func(token CancelToken) {
+		tls.Set("Hello")
+	})
+	executor.Execute(task)
+	task.Get()
+	task2 := WrapWaitResultTask(func(token CancelToken) string {
+		return tls.Get()
+	})
+	executor.Execute(task2)
+	assert.Equal(t, "", task2.Get())
+	executor.Shutdown()
+	assert.True(t, executor.AwaitTermination(time.Second))
+	for _, running := range executor.running {
+		assert.Nil(t, running)
+	}
+}
+
+func TestFixedExecutor_Work_Stopped(t *testing.T) {
+	executor := newFixedExecutor(1, 1, RejectPolicyFail)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	task := WrapWaitTask(func(token CancelToken) {
+		close(started)
+		<-release
+	})
+	executor.Execute(task)
+	<-started
+	run := false
+	task2 := WrapWaitTask(func(token CancelToken) {
+		run = true
+	})
+	executor.Execute(task2)
+	atomic.StoreInt32(&executor.stopped, 1)
+	close(release)
+	<-task2.Done()
+	assert.True(t, task2.IsCanceled())
+	assert.False(t, run)
+	assert.True(t, task.IsDone())
+	executor.Shutdown()
+	assert.True(t, executor.AwaitTermination(time.Second))
+}
+
+func TestFixedExecutor_Work_Panic(t *testing.T) {
+	executor := newFixedExecutor(1, 0, RejectPolicyBlock)
+	task := &panicTask{failed: make(chan any, 1)}
+	executor.Execute(task)
+	err, ok := (<-task.failed).(RuntimeError)
+	assert.True(t, ok)
+	assert.Equal(t, "task panic", err.Message())
+	// the worker is still alive
+	task2 := WrapWaitResultTask(func(token CancelToken) string {
+		return "Hello"
+	})
+	executor.Execute(task2)
+	assert.Equal(t, "Hello", task2.Get())
+	executor.Shutdown()
+	assert.True(t, executor.AwaitTermination(time.Second))
+	assert.Nil(t, executor.running[0])
+}
+
+type panicTask struct {
+	failed chan any
+}
+
+func (task *panicTask) Run() {
+	panic("task panic")
+}
+
+func (task *panicTask) Cancel() {
+}
+
+func (task *panicTask) Fail(error any) {
+	task.failed <- error
+}
@@ -0,0 +1,24 @@
+package routine
+
+// newPromise create a new task which can only be completed by Complete, Cancel or Fail method.
+// The Run method of the returned task will wait until the task is completed.
+func newPromise[T any]() FutureTask[T] {
+	return NewFutureTask(func(task FutureTask[T]) T {
+		return task.Get()
+	})
+}
+
+// completeWith completes the task with the result, or fail the task with the err if it is not nil.
+func completeWith[T any](task FutureTask[T], result T, err RuntimeError) {
+	if err != nil {
+		task.Fail(err)
+		return
+	}
+	task.Complete(result)
+}
+
+func checkTask[T any](task FutureTask[T]) {
+	if task == nil {
+		panic("task can not be nil.")
+	}
+}
@@ -0,0 +1,38 @@
+package routine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPromise(t *testing.T) {
+	task := newPromise[int]()
+	go task.Run()
+	task.Complete(1)
+	assert.Equal(t, 1, task.Get())
+	task.Run()
+	assert.Equal(t, 1, task.Get())
+}
+
+func TestCompleteWith(t *testing.T) {
+	task := newPromise[int]()
+	completeWith(task, 1, nil)
+	assert.Equal(t, 1, task.Get())
+	//
+	err := NewRuntimeError("error")
+	task2 := newPromise[int]()
+	completeWith(task2, 1, err)
+	assert.True(t, task2.IsFailed())
+	_, err2 := task2.TryGet()
+	assert.Same(t, err, err2)
+}
+
+func TestCheckTask(t *testing.T) {
+	assert.Panics(t, func() {
+		checkTask[int](nil)
+	})
+	assert.NotPanics(t, func() {
+		checkTask(newPromise[int]())
+	})
+}
@@ -1,6 +1,7 @@
 package routine
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -18,11 +19,16 @@ const (
 )
 
 type futureTask[TResult any] struct {
-	await    sync.WaitGroup
-	state    taskState
-	callable FutureCallable[TResult]
-	result   TResult
-	error    RuntimeError
+	done      chan struct{}
+	state     taskState
+	callable  FutureCallable[TResult]
+	result    TResult
+	error     RuntimeError
+	mutex     sync.Mutex
+	fired     bool
+	callbacks []Runnable
+	ctxOnce   sync.Once
+	ctx       context.Context
 }
 
 func (task *futureTask[TResult]) IsDone() bool {
@@ -42,15 +48,15 @@ func (task *futureTask[TResult]) Complete(result TResult) {
 	if atomic.CompareAndSwapInt32(&task.state, taskStateNew, taskStateCompleted) ||
 		atomic.CompareAndSwapInt32(&task.state, taskStateRunning, taskStateCompleted) {
 		task.result = result
-		task.await.Done()
+		task.finish()
 	}
 }
 
 func (task *futureTask[TResult]) Cancel() {
 	if atomic.CompareAndSwapInt32(&task.state, taskStateNew, taskStateCanceled) ||
 		atomic.CompareAndSwapInt32(&task.state, taskStateRunning, taskStateCanceled) {
-		task.error = NewRuntimeError("Task was canceled.")
-		task.await.Done()
+		task.error = NewRuntimeError(ErrCanceled)
+		task.finish()
 	}
 }
 
@@ -62,40 +68,127 @@ func (task *futureTask[TResult]) Fail(error any) {
 			runtimeErr = NewRuntimeError(error)
 		}
 		task.error = runtimeErr
-		task.await.Done()
+		task.finish()
 	}
 }
 
-func (task *futureTask[TResult]) Get() TResult {
-	task.await.Wait()
-	if atomic.LoadInt32(&task.state) == taskStateCompleted {
-		return task.result
+func (task *futureTask[TResult]) Done() <-chan struct{} {
+	return task.done
+}
+
+func (task *futureTask[TResult]) Err() error {
+	select {
+	case <-task.done:
+	default:
+		return nil
 	}
-	panic(task.error)
+	if atomic.LoadInt32(&task.state) == taskStateCanceled {
+		return task.error
+	}
+	return nil
+}
+
+func (task *futureTask[TResult]) OnCancel(fun Runnable) {
+	if fun == nil {
+		panic("fun can not be nil.")
+	}
+	task.OnComplete(func(result TResult, err RuntimeError) {
+		if task.IsCanceled() {
+			fun()
+		}
+	})
+}
+
+func (task *futureTask[TResult]) Context() context.Context {
+	// create only once, so the callbacks and the children of the parent will not grow with the calls
+	task.ctxOnce.Do(func() {
+		ctx, cancel := newTokenContext(Context(), task)
+		task.whenDone(Runnable(cancel))
+		task.ctx = ctx
+	})
+	return task.ctx
+}
+
+func (task *futureTask[TResult]) Get() TResult {
+	<-task.done
+	return task.get()
 }
 
 func (task *futureTask[TResult]) GetWithTimeout(timeout time.Duration) TResult {
-	waitChan := make(chan struct{})
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-task.done:
+	case <-timer.C:
+		task.timeout(timeout)
+		<-task.done
+	}
+	return task.get()
+}
+
+func (task *futureTask[TResult]) GetContext(ctx context.Context) (TResult, error) {
+	select {
+	case <-task.done:
+	case <-ctx.Done():
+		if !task.IsDone() {
+			var defaultValue TResult
+			return defaultValue, ctx.Err()
+		}
+		<-task.done
+	}
+	return task.tryGet()
+}
+
+func (task *futureTask[TResult]) CancelWithContext(ctx context.Context) {
+	if ctx.Done() == nil {
+		return
+	}
 	go func() {
-		task.await.Wait()
-		close(waitChan)
+		select {
+		case <-ctx.Done():
+			task.Cancel()
+		case <-task.done:
+		}
 	}()
+}
+
+func (task *futureTask[TResult]) TryGet() (TResult, error) {
+	<-task.done
+	return task.tryGet()
+}
+
+func (task *futureTask[TResult]) TryGetWithTimeout(timeout time.Duration) (TResult, error) {
 	timer := time.NewTimer(timeout)
 	defer timer.Stop()
 	select {
-	case <-waitChan:
-		if atomic.LoadInt32(&task.state) == taskStateCompleted {
-			return task.result
-		}
-		panic(task.error)
+	case <-task.done:
 	case <-timer.C:
 		task.timeout(timeout)
-		task.await.Wait()
-		if atomic.LoadInt32(&task.state) == taskStateCompleted {
-			return task.result
-		}
-		panic(task.error)
+		<-task.done
+	}
+	return task.tryGet()
+}
+
+func (task *futureTask[TResult]) OnComplete(callback FutureCallback[TResult]) {
+	if callback == nil {
+		panic("callback can not be nil.")
 	}
+	ctx := createInheritedMap()
+	task.whenDone(func() {
+		task.invoke(ctx, callback)
+	})
+}
+
+// whenDone registers the fun which will be invoked when the task is done, or invokes it immediately if the task is already done.
+func (task *futureTask[TResult]) whenDone(fun Runnable) {
+	task.mutex.Lock()
+	if task.fired {
+		task.mutex.Unlock()
+		fun()
+		return
+	}
+	task.callbacks = append(task.callbacks, fun)
+	task.mutex.Unlock()
 }
 
 func (task *futureTask[TResult]) Run() {
@@ -113,7 +206,72 @@ func (task *futureTask[TResult]) Run() {
 func (task *futureTask[TResult]) timeout(timeout time.Duration) {
 	if atomic.CompareAndSwapInt32(&task.state, taskStateNew, taskStateCanceled) ||
 		atomic.CompareAndSwapInt32(&task.state, taskStateRunning, taskStateCanceled) {
-		task.error = NewRuntimeError(fmt.Sprintf("Task execution timeout after %v.", timeout))
-		task.await.Done()
+		task.error = NewRuntimeError(&timeoutError{timeout: timeout})
+		task.finish()
+	}
+}
+
+// finish wakes up the waiting coroutines and invokes the callbacks, must be called only once after the state changed.
+func (task *futureTask[TResult]) finish() {
+	close(task.done)
+	task.mutex.Lock()
+	callbacks := task.callbacks
+	task.callbacks = nil
+	task.fired = true
+	task.mutex.Unlock()
+	for _, callback := range callbacks {
+		callback()
+	}
+}
+
+//go:norace
+func (task *futureTask[TResult]) invoke(ctx *threadLocalMap, callback FutureCallback[TResult]) {
+	// catch
+	defer func() {
+		if cause := recover(); cause != nil {
+			handleUncaughtPanic(nil, NewRuntimeError(cause))
+		}
+	}()
+	// restore
+	defer restoreInheritedMap(ctx)()
+	// exec
+	if atomic.LoadInt32(&task.state) == taskStateCompleted {
+		callback(task.result, nil)
+		return
+	}
+	var defaultValue TResult
+	callback(defaultValue, task.error)
+}
+
+func (task *futureTask[TResult]) get() TResult {
+	if atomic.LoadInt32(&task.state) == taskStateCompleted {
+		return task.result
+	}
+	panic(task.error)
+}
+
+func (task *futureTask[TResult]) tryGet() (TResult, error) {
+	if atomic.LoadInt32(&task.state) == taskStateCompleted {
+		return task.result, nil
 	}
+	var defaultValue TResult
+	return defaultValue, task.error
+}
+
+type taskError string
+
+func (e taskError) Error() string {
+	return string(e)
+}
+
+type timeoutError struct {
+	timeout time.Duration
+}
+
+func (e *timeoutError) Error() string {
+	return fmt.Sprintf("Task execution timeout after %v.", e.timeout)
+}
+
+func (e *timeoutError) Is(target error) bool {
+	return target == ErrTimeout
 }
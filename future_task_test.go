@@ -1,6 +1,8 @@
 package routine
 
 import (
+	"context"
+	"errors"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -155,7 +157,7 @@ func TestFutureTask_Fail_Common(t *testing.T) {
 			//
 			line = lines[1]
 			assert.True(t, strings.HasPrefix(line, "   at github.com/timandy/routine.TestFutureTask_Fail_Common."))
-			assert.True(t, strings.HasSuffix(line, "future_task_test.go:169"))
+			assert.True(t, strings.HasSuffix(line, "future_task_test.go:171"))
 		}
 	}()
 	//
@@ -186,14 +188,14 @@ func TestFutureTask_Fail_RuntimeError(t *testing.T) {
 			//
 			line = lines[1]
 			assert.True(t, strings.HasPrefix(line, "   at github.com/timandy/routine.TestFutureTask_Fail_RuntimeError."))
-			assert.True(t, strings.HasSuffix(line, "future_task_test.go:207"))
+			assert.True(t, strings.HasSuffix(line, "future_task_test.go:209"))
 			//
 			line = lines[2]
 			assert.Equal(t, "   --- End of error stack trace ---", line)
 			//
 			line = lines[3]
 			assert.True(t, strings.HasPrefix(line, "   created by github.com/timandy/routine.TestFutureTask_Fail_RuntimeError()"))
-			assert.True(t, strings.HasSuffix(line, "future_task_test.go:201"))
+			assert.True(t, strings.HasSuffix(line, "future_task_test.go:203"))
 		}
 	}()
 	//
@@ -310,6 +312,87 @@ func TestFutureTask_GetWithTimeout_Timeout(t *testing.T) {
 	wg.Wait()
 }
 
+func TestFutureTask_Done(t *testing.T) {
+	task := NewFutureTask[int](func(task FutureTask[int]) int { return 1 })
+	select {
+	case <-task.Done():
+		assert.Fail(t, "task should not be done")
+	default:
+	}
+	go task.Run()
+	<-task.Done()
+	assert.True(t, task.IsDone())
+	assert.Equal(t, 1, task.Get())
+}
+
+func TestFutureTask_GetContext_Complete(t *testing.T) {
+	task := NewFutureTask[int](func(task FutureTask[int]) int { return 1 })
+	go task.Run()
+	result, err := task.GetContext(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, result)
+}
+
+func TestFutureTask_GetContext_Fail(t *testing.T) {
+	task := NewFutureTask[int](func(task FutureTask[int]) int { panic("1") })
+	go task.Run()
+	result, err := task.GetContext(context.Background())
+	assert.Equal(t, 0, result)
+	assert.NotNil(t, err)
+	assert.Implements(t, (*RuntimeError)(nil), err)
+	assert.Equal(t, "1", err.(RuntimeError).Message())
+	//
+	task2 := NewFutureTask[int](func(task FutureTask[int]) int { return 1 })
+	task2.Cancel()
+	result2, err2 := task2.GetContext(context.Background())
+	assert.Equal(t, 0, result2)
+	assert.Equal(t, "Task was canceled.", err2.(RuntimeError).Message())
+}
+
+func TestFutureTask_GetContext_Done(t *testing.T) {
+	task := NewFutureTask[int](func(task FutureTask[int]) int { return 1 })
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	result, err := task.GetContext(ctx)
+	assert.Equal(t, 0, result)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.False(t, task.IsDone())
+	//
+	task.Complete(2)
+	result2, err2 := task.GetContext(ctx)
+	assert.Nil(t, err2)
+	assert.Equal(t, 2, result2)
+}
+
+func TestFutureTask_CancelWithContext(t *testing.T) {
+	task := NewFutureTask[int](func(task FutureTask[int]) int { return 1 })
+	task.CancelWithContext(context.Background())
+	task.Run()
+	assert.Equal(t, 1, task.Get())
+	//
+	ctx, cancel := context.WithCancel(context.Background())
+	task2 := NewFutureTask[int](func(task FutureTask[int]) int {
+		<-task.Done()
+		return 1
+	})
+	task2.CancelWithContext(ctx)
+	go task2.Run()
+	cancel()
+	assert.Panics(t, func() {
+		task2.Get()
+	})
+	assert.True(t, task2.IsCanceled())
+	assert.Equal(t, "Task was canceled.", task2.(*futureTask[int]).error.Message())
+	//
+	ctx3, cancel3 := context.WithCancel(context.Background())
+	task3 := NewFutureTask[int](func(task FutureTask[int]) int { return 1 })
+	task3.CancelWithContext(ctx3)
+	task3.Run()
+	cancel3()
+	assert.Equal(t, 1, task3.Get())
+	assert.False(t, task3.IsCanceled())
+}
+
 func TestFutureTask_Run_AfterCancel(t *testing.T) {
 	run := false
 	task := NewFutureTask(func(task FutureTask[*int]) *int {
@@ -417,18 +500,18 @@ func TestFutureTask_Run_Error(t *testing.T) {
 		//
 		line = lines[1]
 		assert.True(t, strings.HasPrefix(line, "   at github.com/timandy/routine.TestFutureTask_Run_Error."))
-		assert.True(t, strings.HasSuffix(line, "future_task_test.go:397"))
+		assert.True(t, strings.HasSuffix(line, "future_task_test.go:480"))
 		//
 		line = lines[2]
 		assert.True(t, strings.HasPrefix(line, "   at github.com/timandy/routine.(*futureTask[...]).Run()"))
-		assert.True(t, strings.HasSuffix(line, "future_task.go:108"))
+		assert.True(t, strings.HasSuffix(line, "future_task.go:201"))
 		//
 		line = lines[3]
 		assert.Equal(t, "   --- End of error stack trace ---", line)
 		//
 		line = lines[4]
 		assert.True(t, strings.HasPrefix(line, "   created by github.com/timandy/routine.TestFutureTask_Run_Error()"))
-		assert.True(t, strings.HasSuffix(line, "future_task_test.go:399"))
+		assert.True(t, strings.HasSuffix(line, "future_task_test.go:482"))
 	}()
 	task.Get()
 	assert.Fail(t, "should not be here")
@@ -464,18 +547,18 @@ func TestFutureTask_Run_RuntimeError(t *testing.T) {
 		//
 		line = lines[1]
 		assert.True(t, strings.HasPrefix(line, "   at github.com/timandy/routine.TestFutureTask_Run_RuntimeError."))
-		assert.True(t, strings.HasSuffix(line, "future_task_test.go:443"))
+		assert.True(t, strings.HasSuffix(line, "future_task_test.go:526"))
 		//
 		line = lines[2]
 		assert.True(t, strings.HasPrefix(line, "   at github.com/timandy/routine.(*futureTask[...]).Run()"))
-		assert.True(t, strings.HasSuffix(line, "future_task.go:108"))
+		assert.True(t, strings.HasSuffix(line, "future_task.go:201"))
 		//
 		line = lines[3]
 		assert.Equal(t, "   --- End of error stack trace ---", line)
 		//
 		line = lines[4]
 		assert.True(t, strings.HasPrefix(line, "   created by github.com/timandy/routine.TestFutureTask_Run_RuntimeError()"))
-		assert.True(t, strings.HasSuffix(line, "future_task_test.go:446"))
+		assert.True(t, strings.HasSuffix(line, "future_task_test.go:529"))
 	}()
 	task.Get()
 	assert.Fail(t, "should not be here")
@@ -608,7 +691,7 @@ func TestFutureTask_Routine_TimeoutThenComplete(t *testing.T) {
 		ft.result = 1
 		assert.True(t, atomic.CompareAndSwapInt32(&ft.state, taskStateRunning, taskStateCompleted))
 		time.Sleep(50 * time.Millisecond)
-		ft.await.Done()
+		close(ft.done)
 	})
 	assert.Equal(t, 1, task.GetWithTimeout(10*time.Millisecond))
 	assert.Equal(t, 1, task.Get())
@@ -627,7 +710,7 @@ func TestFutureTask_Routine_TimeoutThenCancel(t *testing.T) {
 		ft.error = NewRuntimeError("canceled.")
 		assert.True(t, atomic.CompareAndSwapInt32(&ft.state, taskStateRunning, taskStateCanceled))
 		time.Sleep(50 * time.Millisecond)
-		ft.await.Done()
+		close(ft.done)
 	})
 	assert.Panics(t, func() {
 		task.GetWithTimeout(10 * time.Millisecond)
@@ -654,7 +737,7 @@ func TestFutureTask_Routine_TimeoutThenFail(t *testing.T) {
 		ft.error = NewRuntimeError("failed.")
 		assert.True(t, atomic.CompareAndSwapInt32(&ft.state, taskStateRunning, taskStateFailed))
 		time.Sleep(50 * time.Millisecond)
-		ft.await.Done()
+		close(ft.done)
 	})
 	assert.Panics(t, func() {
 		task.GetWithTimeout(10 * time.Millisecond)
@@ -669,3 +752,250 @@ func TestFutureTask_Routine_TimeoutThenFail(t *testing.T) {
 	//
 	wg.Wait()
 }
+
+func TestFutureTask_TryGet(t *testing.T) {
+	task := NewFutureTask[int](func(task FutureTask[int]) int { return 1 })
+	go task.Run()
+	result, err := task.TryGet()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, result)
+	//
+	task2 := NewFutureTask[int](func(task FutureTask[int]) int { panic("error") })
+	go task2.Run()
+	result2, err2 := task2.TryGet()
+	assert.Equal(t, 0, result2)
+	assert.Equal(t, "error", err2.(RuntimeError).Message())
+	assert.False(t, errors.Is(err2, ErrCanceled))
+	assert.False(t, errors.Is(err2, ErrTimeout))
+	//
+	task3 := NewFutureTask[int](func(task FutureTask[int]) int { return 1 })
+	task3.Cancel()
+	result3, err3 := task3.TryGet()
+	assert.Equal(t, 0, result3)
+	assert.Equal(t, "Task was canceled.", err3.(RuntimeError).Message())
+	assert.Nil(t, err3.(RuntimeError).Cause())
+	assert.True(t, errors.Is(err3, ErrCanceled))
+	assert.False(t, errors.Is(err3, ErrTimeout))
+	assert.Equal(t, ErrCanceled, errors.Unwrap(err3))
+}
+
+func TestFutureTask_TryGetWithTimeout(t *testing.T) {
+	task := NewFutureTask[int](func(task FutureTask[int]) int { return 1 })
+	go task.Run()
+	result, err := task.TryGetWithTimeout(time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, result)
+	//
+	task2 := NewFutureTask[int](func(task FutureTask[int]) int { return 1 })
+	result2, err2 := task2.TryGetWithTimeout(time.Millisecond)
+	assert.Equal(t, 0, result2)
+	assert.True(t, task2.IsCanceled())
+	assert.Equal(t, "Task execution timeout after 1ms.", err2.(RuntimeError).Message())
+	assert.Nil(t, err2.(RuntimeError).Cause())
+	assert.True(t, errors.Is(err2, ErrTimeout))
+	assert.False(t, errors.Is(err2, ErrCanceled))
+	//
+	result3, err3 := task2.TryGet()
+	assert.Equal(t, 0, result3)
+	assert.Same(t, err2, err3)
+}
+
+func TestTimeoutError(t *testing.T) {
+	err := &timeoutError{timeout: time.Second}
+	assert.Equal(t, "Task execution timeout after 1s.", err.Error())
+	assert.True(t, errors.Is(err, ErrTimeout))
+	assert.False(t, errors.Is(err, ErrCanceled))
+}
+
+func TestFutureTask_OnComplete(t *testing.T) {
+	tls := NewInheritableThreadLocal[string]()
+	tls2 := NewThreadLocal[string]()
+	tls.Set("Hello")
+	tls2.Set("World")
+	task := NewFutureTask[int](func(task FutureTask[int]) int { return 1 })
+	count := int32(0)
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+	task.OnComplete(func(result int, err RuntimeError) {
+		defer wg.Done()
+		assert.Equal(t, 1, result)
+		assert.Nil(t, err)
+		assert.Equal(t, "Hello", tls.Get())
+		assert.Equal(t, "", tls2.Get())
+		atomic.AddInt32(&count, 1)
+	})
+	task.OnComplete(func(result int, err RuntimeError) {
+		defer wg.Done()
+		atomic.AddInt32(&count, 1)
+		panic("error")
+	})
+	assert.Panics(t, func() {
+		task.OnComplete(nil)
+	})
+	go func() {
+		tls.Set("World")
+		task.Run()
+		assert.Equal(t, "World", tls.Get())
+	}()
+	wg.Wait()
+	assert.Equal(t, int32(2), atomic.LoadInt32(&count))
+	//
+	run := false
+	task.OnComplete(func(result int, err RuntimeError) {
+		run = true
+		assert.Equal(t, 1, result)
+	})
+	assert.True(t, run)
+	task.Complete(2)
+	task.Cancel()
+	assert.Equal(t, int32(2), atomic.LoadInt32(&count))
+	assert.Equal(t, "World", tls2.Get())
+}
+
+func TestFutureTask_OnComplete_Fail(t *testing.T) {
+	task := NewFutureTask[int](func(task FutureTask[int]) int { panic("error") })
+	run := false
+	task.OnComplete(func(result int, err RuntimeError) {
+		run = true
+		assert.Equal(t, 0, result)
+		assert.Equal(t, "error", err.Message())
+	})
+	task.Run()
+	assert.True(t, run)
+	//
+	task2 := NewFutureTask[int](func(task FutureTask[int]) int { return 1 })
+	run2 := false
+	task2.OnComplete(func(result int, err RuntimeError) {
+		run2 = true
+		assert.Equal(t, 0, result)
+		assert.True(t, errors.Is(err, ErrTimeout))
+	})
+	_, err := task2.TryGetWithTimeout(time.Millisecond)
+	assert.NotNil(t, err)
+	assert.True(t, run2)
+}
+
+func TestFutureTask_OnComplete_Concurrency(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		task := NewFutureTask[int](func(task FutureTask[int]) int { return 1 })
+		count := int32(0)
+		wg := &sync.WaitGroup{}
+		wg.Add(concurrency)
+		for j := 0; j < concurrency; j++ {
+			go task.OnComplete(func(result int, err RuntimeError) {
+				atomic.AddInt32(&count, 1)
+				wg.Done()
+			})
+		}
+		go task.Run()
+		wg.Wait()
+		assert.Equal(t, int32(concurrency), atomic.LoadInt32(&count))
+	}
+}
+
+func TestFutureTask_Err(t *testing.T) {
+	task := NewFutureTask[any](func(task FutureTask[any]) any { return nil })
+	assert.Nil(t, task.Err())
+	task.Cancel()
+	err := task.Err()
+	assert.NotNil(t, err)
+	assert.True(t, errors.Is(err, ErrCanceled))
+	//
+	task2 := NewFutureTask[any](func(task FutureTask[any]) any { return nil })
+	task2.Complete(nil)
+	assert.Nil(t, task2.Err())
+	//
+	task3 := NewFutureTask[any](func(task FutureTask[any]) any { return nil })
+	task3.Fail("error")
+	assert.Nil(t, task3.Err())
+	//
+	task4 := NewFutureTask[any](func(task FutureTask[any]) any { return nil })
+	_, _ = task4.TryGetWithTimeout(time.Millisecond)
+	assert.True(t, errors.Is(task4.Err(), ErrTimeout))
+}
+
+func TestFutureTask_OnCancel(t *testing.T) {
+	tls := NewInheritableThreadLocal[string]()
+	tls.Set("Hello")
+	task := NewFutureTask[any](func(task FutureTask[any]) any { return nil })
+	var value string
+	count := 0
+	task.OnCancel(func() {
+		count++
+		value = tls.Get()
+	})
+	assert.Equal(t, 0, count)
+	task.Cancel()
+	task.Cancel()
+	assert.Equal(t, 1, count)
+	assert.Equal(t, "Hello", value)
+	//
+	task.OnCancel(func() {
+		count++
+	})
+	assert.Equal(t, 2, count)
+	//
+	task2 := NewFutureTask[any](func(task FutureTask[any]) any { return nil })
+	task2.OnCancel(func() {
+		count++
+	})
+	task2.Complete(nil)
+	assert.Equal(t, 2, count)
+	//
+	assert.Panics(t, func() {
+		task2.OnCancel(nil)
+	})
+}
+
+func TestFutureTask_Context(t *testing.T) {
+	task := NewFutureTask[any](func(task FutureTask[any]) any { return nil })
+	ctx := task.Context()
+	assert.Nil(t, ctx.Err())
+	select {
+	case <-ctx.Done():
+		assert.Fail(t, "should not be done")
+	default:
+	}
+	task.Cancel()
+	<-ctx.Done()
+	assert.Equal(t, context.Canceled, ctx.Err())
+	//
+	task2 := NewFutureTask[any](func(task FutureTask[any]) any { return nil })
+	ctx2 := task2.Context()
+	_, _ = task2.TryGetWithTimeout(time.Millisecond)
+	<-ctx2.Done()
+	assert.Equal(t, context.DeadlineExceeded, ctx2.Err())
+	//
+	task3 := NewFutureTask[any](func(task FutureTask[any]) any { return nil })
+	ctx3 := task3.Context()
+	task3.Complete(nil)
+	<-ctx3.Done()
+	assert.Equal(t, context.Canceled, ctx3.Err())
+	//
+	parent, cancel := context.WithCancel(context.Background())
+	WithContext(parent)
+	defer contextThreadLocal.Remove()
+	task4 := NewFutureTask[any](func(task FutureTask[any]) any { return nil })
+	ctx4 := task4.Context()
+	cancel()
+	<-ctx4.Done()
+	assert.Equal(t, context.Canceled, ctx4.Err())
+	assert.False(t, task4.IsDone())
+}
+
+func TestFutureTask_Context_Cached(t *testing.T) {
+	task := NewFutureTask[any](func(task FutureTask[any]) any { return nil })
+	ctx := task.Context()
+	assert.Same(t, ctx, task.Context())
+	fut := task.(*futureTask[any])
+	assert.Len(t, fut.callbacks, 1)
+	//
+	WithContext(context.WithValue(context.Background(), contextKey{}, "Hello"))
+	defer contextThreadLocal.Remove()
+	assert.Same(t, ctx, task.Context())
+	assert.Nil(t, task.Context().Value(contextKey{}))
+	assert.Len(t, fut.callbacks, 1)
+	task.Cancel()
+	<-ctx.Done()
+	assert.Equal(t, context.Canceled, ctx.Err())
+}
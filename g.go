@@ -3,6 +3,7 @@ package routine
 import (
 	"fmt"
 	"reflect"
+	"sync/atomic"
 	"unsafe"
 )
 
@@ -19,6 +20,26 @@ func (g *g) gopc() uintptr {
 	return *(*uintptr)(add(unsafe.Pointer(g), offsetGopc))
 }
 
+//go:norace
+func (g *g) atomicstatus() uint32 {
+	return atomic.LoadUint32((*uint32)(add(unsafe.Pointer(g), offsetAtomicstatus)))
+}
+
+//go:norace
+func (g *g) startpc() uintptr {
+	return *(*uintptr)(add(unsafe.Pointer(g), offsetStartpc))
+}
+
+// parentGoid returns the goid of the goroutine which created this goroutine, returns 0 if the field is not available before go1.21.
+//
+//go:norace
+func (g *g) parentGoid() uint64 {
+	if offsetParentGoid == 0 {
+		return 0
+	}
+	return *(*uint64)(add(unsafe.Pointer(g), offsetParentGoid))
+}
+
 //go:norace
 func (g *g) getPanicOnFault() bool {
 	return *(*bool)(add(unsafe.Pointer(g), offsetPaniconfault))
@@ -60,6 +81,15 @@ func offset(t reflect.Type, f string) uintptr {
 	panic(fmt.Sprintf("No such field '%v' of struct '%v.%v'.", f, t.PkgPath(), t.Name()))
 }
 
+// optionalOffset returns the offset of the specified field, returns 0 if the field does not exist.
+func optionalOffset(t reflect.Type, f string) uintptr {
+	field, found := t.FieldByName(f)
+	if found {
+		return field.Offset
+	}
+	return 0
+}
+
 // add pointer addition operation.
 func add(p unsafe.Pointer, x uintptr) unsafe.Pointer {
 	return unsafe.Pointer(uintptr(p) + x)
@@ -30,6 +30,20 @@ func TestG_Gopc(t *testing.T) {
 	})
 }
 
+func TestG_Status(t *testing.T) {
+	runTest(t, func() {
+		gp := getg()
+		runtime.GC()
+		assert.Equal(t, uint32(2), gp.atomicstatus())
+		assert.Greater(t, int64(gp.startpc()), int64(0))
+		if offsetParentGoid != 0 {
+			assert.Greater(t, gp.parentGoid(), uint64(0))
+		} else {
+			assert.Equal(t, uint64(0), gp.parentGoid())
+		}
+	})
+}
+
 func TestG_PanicOnFault(t *testing.T) {
 	runTest(t, func() {
 		gp := getg()
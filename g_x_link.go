@@ -9,6 +9,9 @@ var (
 	offsetPaniconfault uintptr
 	offsetGopc         uintptr
 	offsetLabels       uintptr
+	offsetAtomicstatus uintptr
+	offsetStartpc      uintptr
+	offsetParentGoid   uintptr
 	offsetThreadLocals uintptr
 )
 
@@ -18,5 +21,8 @@ func init() {
 	offsetPaniconfault = offset(gt, "paniconfault")
 	offsetGopc = offset(gt, "gopc")
 	offsetLabels = offset(gt, "labels")
+	offsetAtomicstatus = offset(gt, "atomicstatus")
+	offsetStartpc = offset(gt, "startpc")
+	offsetParentGoid = optionalOffset(gt, "parentGoid")
 	offsetThreadLocals = offset(gt, "threadLocals")
 }
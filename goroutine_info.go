@@ -0,0 +1,168 @@
+package routine
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+const (
+	goroutinePrefix      = "goroutine "
+	goroutineCreatedBy   = "created by "
+	goroutineInGoroutine = " in goroutine "
+	goroutineStackSize   = 64 << 10
+)
+
+var goroutineStatusNames = map[GoroutineStatus]string{
+	GoroutineStatusIdle:      "idle",
+	GoroutineStatusRunnable:  "runnable",
+	GoroutineStatusRunning:   "running",
+	GoroutineStatusSyscall:   "syscall",
+	GoroutineStatusWaiting:   "waiting",
+	GoroutineStatusDead:      "dead",
+	GoroutineStatusCopystack: "copystack",
+	GoroutineStatusPreempted: "preempted",
+}
+
+var goroutineStatusValues = func() map[string]GoroutineStatus {
+	values := make(map[string]GoroutineStatus, len(goroutineStatusNames))
+	for status, name := range goroutineStatusNames {
+		values[name] = status
+	}
+	return values
+}()
+
+func goroutineStatusString(s GoroutineStatus) string {
+	prefix := ""
+	if s&GoroutineStatusScan != 0 {
+		prefix = "scan "
+		s &^= GoroutineStatusScan
+	}
+	if name, ok := goroutineStatusNames[s]; ok {
+		return prefix + name
+	}
+	return prefix + "GoroutineStatus(" + strconv.FormatUint(uint64(s), 10) + ")"
+}
+
+func newGoroutineInfo(gp *g) GoroutineInfo {
+	return GoroutineInfo{
+		Goid:       gp.goid(),
+		ParentGoid: gp.parentGoid(),
+		Status:     GoroutineStatus(gp.atomicstatus()),
+		StartPc:    gp.startpc(),
+		CreatedPc:  gp.gopc(),
+	}
+}
+
+// goroutines returns the current goroutine read from the g and the other goroutines resolved from the traceback.
+func goroutines() []GoroutineInfo {
+	current := Current()
+	buf := make([]byte, goroutineStackSize)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, len(buf)*2)
+	}
+	infos := parseGoroutines(string(buf))
+	result := make([]GoroutineInfo, 0, len(infos))
+	result = append(result, current)
+	for _, info := range infos {
+		if info.Goid != current.Goid {
+			result = append(result, info)
+		}
+	}
+	return result
+}
+
+// parseGoroutines parses the traceback of all goroutines, the goroutines are separated by blank lines.
+func parseGoroutines(traceback string) []GoroutineInfo {
+	var infos []GoroutineInfo
+	for _, block := range strings.Split(traceback, "\n\n") {
+		if info, ok := parseGoroutine(block); ok {
+			infos = append(infos, info)
+		}
+	}
+	return infos
+}
+
+// parseGoroutine parses the traceback of a goroutine, e.g.
+//
+//	goroutine 7 [chan receive, 2 minutes]:
+//	main.main.func1()
+//		/path/main.go:15 +0x19
+//	created by main.main in goroutine 1
+//		/path/main.go:14 +0xe5
+func parseGoroutine(block string) (GoroutineInfo, bool) {
+	lines := strings.Split(strings.Trim(block, "\n"), "\n")
+	header := lines[0]
+	open := strings.IndexByte(header, '[')
+	if !strings.HasPrefix(header, goroutinePrefix) || open < 0 || !strings.HasSuffix(header, "]:") {
+		return GoroutineInfo{}, false
+	}
+	fields := strings.Fields(header[len(goroutinePrefix):open])
+	if len(fields) == 0 {
+		return GoroutineInfo{}, false
+	}
+	goid, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return GoroutineInfo{}, false
+	}
+	info := GoroutineInfo{Goid: goid}
+	info.Status, info.WaitReason = parseGoroutineState(header[open+1 : len(header)-2])
+	var last Frame
+	elided := false
+	for i := 1; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case strings.HasPrefix(line, goroutineCreatedBy):
+			function := line[len(goroutineCreatedBy):]
+			if in := strings.Index(function, goroutineInGoroutine); in >= 0 {
+				info.ParentGoid, _ = strconv.ParseUint(function[in+len(goroutineInGoroutine):], 10, 64)
+				function = function[:in]
+			}
+			info.createdFrame, i = parseFrame(function, lines, i)
+		case strings.HasPrefix(line, "..."):
+			elided = true
+		case strings.HasSuffix(line, ")") && !strings.HasPrefix(line, "\t"):
+			if paren := strings.LastIndexByte(line, '('); paren > 0 {
+				last, i = parseFrame(line[:paren], lines, i)
+			}
+		}
+	}
+	if !elided {
+		info.startFrame = last
+	}
+	return info, true
+}
+
+// parseGoroutineState returns the status and the wait reason of the state in the header, e.g. "running", "chan receive, 2 minutes", "sleep, locked to thread".
+func parseGoroutineState(state string) (GoroutineStatus, string) {
+	if comma := strings.Index(state, ", "); comma >= 0 {
+		state = state[:comma]
+	}
+	if status, ok := goroutineStatusValues[state]; ok {
+		return status, ""
+	}
+	// the runtime prints the wait reason instead of the status if the goroutine is waiting
+	return GoroutineStatusWaiting, state
+}
+
+// parseFrame returns the frame of the function and the index of the last line consumed, the file line follows the function line.
+func parseFrame(function string, lines []string, i int) (Frame, int) {
+	frame := Frame{Function: function, Package: funcPackage(function)}
+	if i+1 >= len(lines) || !strings.HasPrefix(lines[i+1], "\t") {
+		return frame, i
+	}
+	location := lines[i+1][1:]
+	if offset := strings.LastIndex(location, " +0x"); offset >= 0 {
+		location = location[:offset]
+	}
+	if colon := strings.LastIndexByte(location, ':'); colon >= 0 {
+		frame.File = location[:colon]
+		frame.Line, _ = strconv.Atoi(location[colon+1:])
+	}
+	return frame, i + 1
+}
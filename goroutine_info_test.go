@@ -0,0 +1,119 @@
+package routine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoroutineStatusString(t *testing.T) {
+	for status, name := range goroutineStatusNames {
+		assert.Equal(t, name, goroutineStatusString(status))
+		assert.Equal(t, "scan "+name, goroutineStatusString(status|GoroutineStatusScan))
+	}
+	assert.Equal(t, "GoroutineStatus(7)", goroutineStatusString(7))
+}
+
+func TestNewGoroutineInfo(t *testing.T) {
+	gp := getg()
+	info := newGoroutineInfo(gp)
+	assert.Equal(t, gp.goid(), info.Goid)
+	assert.Equal(t, gp.parentGoid(), info.ParentGoid)
+	assert.Equal(t, GoroutineStatus(gp.atomicstatus()), info.Status)
+	assert.Equal(t, "", info.WaitReason)
+	assert.Equal(t, gp.startpc(), info.StartPc)
+	assert.Equal(t, gp.gopc(), info.CreatedPc)
+}
+
+func TestParseGoroutines(t *testing.T) {
+	traceback := `goroutine 1 [running]:
+main.main()
+	/path/main.go:20 +0x130
+
+goroutine 6 [sync.Mutex.Lock, 2 minutes]:
+internal/sync.runtime_SemacquireMutex(0x0?, 0x0?, 0x0?)
+	/usr/local/go/src/runtime/sema.go:95 +0x25
+main.main.func1()
+	/path/main.go:13 +0x2c
+created by main.main in goroutine 1
+	/path/main.go:12 +0x8b
+
+goroutine 7 [chan receive]:
+example.com/a%2eb.worker(0xc000010000)
+	/path/worker.go:8 +0x1f
+created by example.com/a%2eb.Start
+	/path/worker.go:3 +0x45
+
+goroutine 8 [runnable, locked to thread]:
+...additional frames elided...
+created by main.main in goroutine 1
+	/path/main.go:14 +0x9c
+
+goroutine 9 [running]:
+	goroutine running on other thread; stack unavailable
+created by main.main in goroutine 1
+	/path/main.go:15 +0xa8
+`
+	infos := parseGoroutines(traceback)
+	assert.Len(t, infos, 5)
+	//
+	assert.Equal(t, uint64(1), infos[0].Goid)
+	assert.Equal(t, uint64(0), infos[0].ParentGoid)
+	assert.Equal(t, GoroutineStatusRunning, infos[0].Status)
+	assert.Equal(t, "", infos[0].WaitReason)
+	assert.Equal(t, Frame{Function: "main.main", File: "/path/main.go", Line: 20, Package: "main"}, infos[0].StartFrame())
+	assert.Equal(t, Frame{}, infos[0].CreatedFrame())
+	//
+	assert.Equal(t, uint64(6), infos[1].Goid)
+	assert.Equal(t, uint64(1), infos[1].ParentGoid)
+	assert.Equal(t, GoroutineStatusWaiting, infos[1].Status)
+	assert.Equal(t, "sync.Mutex.Lock", infos[1].WaitReason)
+	assert.Equal(t, Frame{Function: "main.main.func1", File: "/path/main.go", Line: 13, Package: "main"}, infos[1].StartFrame())
+	assert.Equal(t, Frame{Function: "main.main", File: "/path/main.go", Line: 12, Package: "main"}, infos[1].CreatedFrame())
+	//
+	assert.Equal(t, uint64(7), infos[2].Goid)
+	assert.Equal(t, uint64(0), infos[2].ParentGoid)
+	assert.Equal(t, GoroutineStatusWaiting, infos[2].Status)
+	assert.Equal(t, "chan receive", infos[2].WaitReason)
+	assert.Equal(t, Frame{Function: "example.com/a%2eb.worker", File: "/path/worker.go", Line: 8, Package: "example.com/a.b"}, infos[2].StartFrame())
+	assert.Equal(t, Frame{Function: "example.com/a%2eb.Start", File: "/path/worker.go", Line: 3, Package: "example.com/a.b"}, infos[2].CreatedFrame())
+	//
+	assert.Equal(t, uint64(8), infos[3].Goid)
+	assert.Equal(t, GoroutineStatusRunnable, infos[3].Status)
+	assert.Equal(t, Frame{}, infos[3].StartFrame())
+	assert.Equal(t, 14, infos[3].CreatedFrame().Line)
+	//
+	assert.Equal(t, uint64(9), infos[4].Goid)
+	assert.Equal(t, GoroutineStatusRunning, infos[4].Status)
+	assert.Equal(t, Frame{}, infos[4].StartFrame())
+	assert.Equal(t, 15, infos[4].CreatedFrame().Line)
+}
+
+func TestParseGoroutine_Invalid(t *testing.T) {
+	_, ok := parseGoroutine("")
+	assert.False(t, ok)
+	_, ok = parseGoroutine("goroutine profile: total 1")
+	assert.False(t, ok)
+	_, ok = parseGoroutine("goroutine [running]:")
+	assert.False(t, ok)
+	_, ok = parseGoroutine("goroutine x [running]:")
+	assert.False(t, ok)
+	info, ok := parseGoroutine("goroutine 3 gp=0xc000002380 m=nil [idle]:")
+	assert.True(t, ok)
+	assert.Equal(t, uint64(3), info.Goid)
+	assert.Equal(t, GoroutineStatusIdle, info.Status)
+}
+
+func TestParseGoroutineState(t *testing.T) {
+	for status, name := range goroutineStatusNames {
+		actual, reason := parseGoroutineState(name)
+		assert.Equal(t, status, actual)
+		assert.Equal(t, "", reason)
+	}
+	status, reason := parseGoroutineState("select (no cases)")
+	assert.Equal(t, GoroutineStatusWaiting, status)
+	assert.Equal(t, "select (no cases)", reason)
+	status, reason = parseGoroutineState("sleep, 5 minutes, locked to thread")
+	assert.Equal(t, GoroutineStatusWaiting, status)
+	assert.Equal(t, "sleep", reason)
+}
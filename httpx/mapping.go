@@ -0,0 +1,47 @@
+// Package httpx provides the net/http middleware and RoundTripper which seed and propagate the goroutine-local context of routine.
+package httpx
+
+import (
+	"net/http"
+
+	"github.com/timandy/routine"
+)
+
+// Mapping maps a http header to a ThreadLocal.
+type Mapping struct {
+	// Header is the name of the http header, it is case-insensitive.
+	Header string
+
+	// ThreadLocal stores the value of the header, an InheritableThreadLocal is recommended so that the value can be inherited by the sub goroutines.
+	ThreadLocal routine.ThreadLocal[string]
+}
+
+func checkMappings(mappings []Mapping) {
+	for _, mapping := range mappings {
+		if len(mapping.Header) == 0 {
+			panic("header can not be empty.")
+		}
+		if mapping.ThreadLocal == nil {
+			panic("threadLocal can not be nil.")
+		}
+	}
+}
+
+// readHeaders sets the ThreadLocals from the headers, the ThreadLocals of the absent headers are not set.
+func readHeaders(header http.Header, mappings []Mapping) {
+	for _, mapping := range mappings {
+		if values := header.Values(mapping.Header); len(values) > 0 {
+			mapping.ThreadLocal.Set(values[0])
+		}
+	}
+}
+
+// writeHeaders sets the headers from the ThreadLocals, the existing headers and the unset ThreadLocals are skipped.
+func writeHeaders(header http.Header, mappings []Mapping) {
+	for _, mapping := range mappings {
+		if len(header.Values(mapping.Header)) > 0 || !mapping.ThreadLocal.IsSet() {
+			continue
+		}
+		header.Set(mapping.Header, mapping.ThreadLocal.Get())
+	}
+}
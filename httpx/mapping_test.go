@@ -0,0 +1,52 @@
+package httpx
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/timandy/routine"
+)
+
+func TestCheckMappings(t *testing.T) {
+	assert.NotPanics(t, func() {
+		checkMappings(nil)
+		checkMappings([]Mapping{{Header: "X-Request-Id", ThreadLocal: routine.NewThreadLocal[string]()}})
+	})
+	assert.Panics(t, func() {
+		checkMappings([]Mapping{{Header: "", ThreadLocal: routine.NewThreadLocal[string]()}})
+	})
+	assert.Panics(t, func() {
+		checkMappings([]Mapping{{Header: "X-Request-Id"}})
+	})
+}
+
+func TestReadHeaders(t *testing.T) {
+	task := routine.GoWait(func(token routine.CancelToken) {
+		tls := routine.NewThreadLocal[string]()
+		tls2 := routine.NewThreadLocal[string]()
+		header := http.Header{}
+		header.Set("X-Request-Id", "abc")
+		readHeaders(header, []Mapping{{Header: "x-request-id", ThreadLocal: tls}, {Header: "X-Tenant", ThreadLocal: tls2}})
+		assert.Equal(t, "abc", tls.Get())
+		assert.False(t, tls2.IsSet())
+	})
+	task.Get()
+}
+
+func TestWriteHeaders(t *testing.T) {
+	task := routine.GoWait(func(token routine.CancelToken) {
+		tls := routine.NewThreadLocal[string]()
+		tls2 := routine.NewThreadLocal[string]()
+		tls3 := routine.NewThreadLocal[string]()
+		tls.Set("abc")
+		tls3.Set("ignored")
+		header := http.Header{}
+		header.Set("X-Tenant", "t1")
+		writeHeaders(header, []Mapping{{Header: "X-Request-Id", ThreadLocal: tls}, {Header: "X-User", ThreadLocal: tls2}, {Header: "x-tenant", ThreadLocal: tls3}})
+		assert.Equal(t, "abc", header.Get("X-Request-Id"))
+		assert.Empty(t, header.Values("X-User"))
+		assert.Equal(t, []string{"t1"}, header.Values("X-Tenant"))
+	})
+	task.Get()
+}
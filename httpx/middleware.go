@@ -0,0 +1,24 @@
+package httpx
+
+import (
+	"net/http"
+
+	"github.com/timandy/routine"
+)
+
+// Middleware returns a http.Handler which serves each request in a clean goroutine-local context.
+// The ThreadLocals are set from the request headers and the request context is stored by routine.WithContext before calling next.
+// The previous goroutine-local context is restored when next returns or panics.
+func Middleware(next http.Handler, mappings ...Mapping) http.Handler {
+	if next == nil {
+		panic("next can not be nil.")
+	}
+	checkMappings(mappings)
+	mappings = append([]Mapping(nil), mappings...)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer routine.EmptySnapshot().Restore()()
+		routine.WithContext(r.Context())
+		readHeaders(r.Header, mappings)
+		next.ServeHTTP(w, r)
+	})
+}
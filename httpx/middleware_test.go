@@ -0,0 +1,83 @@
+package httpx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/timandy/routine"
+)
+
+type contextKey struct{}
+
+func TestMiddleware(t *testing.T) {
+	assert.Panics(t, func() {
+		Middleware(nil)
+	})
+	//
+	tls := routine.NewInheritableThreadLocal[string]()
+	tls2 := routine.NewThreadLocal[string]()
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "abc", tls.Get())
+		assert.False(t, tls2.IsSet())
+		assert.Same(t, r.Context(), routine.Context())
+		assert.Equal(t, "value", routine.Context().Value(contextKey{}))
+		task := routine.GoWait(func(token routine.CancelToken) {
+			assert.Equal(t, "abc", tls.Get())
+		})
+		task.Get()
+	}), Mapping{Header: "X-Request-Id", ThreadLocal: tls}, Mapping{Header: "X-User", ThreadLocal: tls2})
+	//
+	task := routine.GoWait(func(token routine.CancelToken) {
+		tls.Set("outer")
+		tls2.Set("outer")
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req = req.WithContext(context.WithValue(req.Context(), contextKey{}, "value"))
+		req.Header.Set("X-Request-Id", "abc")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		assert.Equal(t, "outer", tls.Get())
+		assert.Equal(t, "outer", tls2.Get())
+		assert.Equal(t, context.Background(), routine.Context())
+	})
+	task.Get()
+}
+
+func TestMiddleware_Panic(t *testing.T) {
+	tls := routine.NewThreadLocal[string]()
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "abc", tls.Get())
+		panic("handler error")
+	}), Mapping{Header: "X-Request-Id", ThreadLocal: tls})
+	//
+	task := routine.GoWait(func(token routine.CancelToken) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Request-Id", "abc")
+		assert.Panics(t, func() {
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+		})
+		assert.False(t, tls.IsSet())
+	})
+	task.Get()
+}
+
+func TestMiddleware_Server(t *testing.T) {
+	tls := routine.NewInheritableThreadLocal[string]()
+	server := httptest.NewServer(Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(tls.Get()))
+	}), Mapping{Header: "X-Request-Id", ThreadLocal: tls}))
+	defer server.Close()
+	//
+	client := &http.Client{Transport: NewTransport(nil, Mapping{Header: "X-Request-Id", ThreadLocal: tls})}
+	task := routine.GoWait(func(token routine.CancelToken) {
+		tls.Set("abc")
+		resp, err := client.Get(server.URL)
+		assert.Nil(t, err)
+		defer resp.Body.Close()
+		body := make([]byte, 3)
+		n, _ := resp.Body.Read(body)
+		assert.Equal(t, "abc", string(body[:n]))
+	})
+	task.Get()
+}
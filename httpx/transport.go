@@ -0,0 +1,28 @@
+package httpx
+
+import (
+	"net/http"
+)
+
+// Transport is a http.RoundTripper which writes the ThreadLocals of the current goroutine into the outbound request headers.
+// The headers which are already set in the request are not overwritten.
+type Transport struct {
+	base     http.RoundTripper
+	mappings []Mapping
+}
+
+// NewTransport create a new Transport which sends the requests by base, if base is nil, the http.DefaultTransport is used.
+func NewTransport(base http.RoundTripper, mappings ...Mapping) *Transport {
+	checkMappings(mappings)
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{base: base, mappings: append([]Mapping(nil), mappings...)}
+}
+
+// RoundTrip writes the ThreadLocals into a clone of the request headers, then sends it by the base RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	outReq := req.Clone(req.Context())
+	writeHeaders(outReq.Header, t.mappings)
+	return t.base.RoundTrip(outReq)
+}
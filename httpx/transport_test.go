@@ -0,0 +1,55 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/timandy/routine"
+)
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestNewTransport(t *testing.T) {
+	transport := NewTransport(nil)
+	assert.Same(t, http.DefaultTransport, transport.base)
+	assert.Empty(t, transport.mappings)
+	//
+	base := &http.Transport{}
+	transport2 := NewTransport(base, Mapping{Header: "X-Request-Id", ThreadLocal: routine.NewThreadLocal[string]()})
+	assert.Same(t, base, transport2.base)
+	assert.Len(t, transport2.mappings, 1)
+	//
+	assert.Panics(t, func() {
+		NewTransport(nil, Mapping{Header: "X-Request-Id"})
+	})
+}
+
+func TestTransport_RoundTrip(t *testing.T) {
+	tls := routine.NewThreadLocal[string]()
+	tls2 := routine.NewThreadLocal[string]()
+	var received http.Header
+	transport := NewTransport(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		received = req.Header
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	}), Mapping{Header: "X-Request-Id", ThreadLocal: tls}, Mapping{Header: "X-Tenant", ThreadLocal: tls2})
+	//
+	task := routine.GoWait(func(token routine.CancelToken) {
+		tls.Set("abc")
+		tls2.Set("t2")
+		req := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+		req.Header.Set("X-Tenant", "t1")
+		resp, err := transport.RoundTrip(req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "abc", received.Get("X-Request-Id"))
+		assert.Equal(t, "t1", received.Get("X-Tenant"))
+		assert.Empty(t, req.Header.Get("X-Request-Id"))
+	})
+	task.Get()
+}
@@ -0,0 +1,118 @@
+package routine
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// inheritFunc returns the entry of the child from the entry of the parent, returns unset to skip.
+type inheritFunc func(e entry) entry
+
+type inheritHandler struct {
+	version uint32
+	inherit inheritFunc
+}
+
+type inheritHandlersHolder struct {
+	handlers []*inheritHandler // indexed by the index of inheritable ThreadLocal
+}
+
+var (
+	inheritHandlers      atomic.Value
+	inheritHandlersMutex sync.Mutex
+)
+
+func newInheritFunc[T any](options InheritableThreadLocalOptions[T]) inheritFunc {
+	switch options.Policy {
+	case InheritPolicyClone:
+		return nil
+	case InheritPolicyShare:
+		return func(e entry) entry {
+			return e
+		}
+	case InheritPolicyChildValue:
+		childValue := options.ChildValue
+		if childValue == nil {
+			panic("childValue can not be nil.")
+		}
+		return func(e entry) entry {
+			return entry(childValue(entryValue[T](e)))
+		}
+	case InheritPolicySkip:
+		return func(e entry) entry {
+			return unset
+		}
+	default:
+		panic("unknown inherit policy.")
+	}
+}
+
+func loadInheritHandlers() []*inheritHandler {
+	if holder, ok := inheritHandlers.Load().(*inheritHandlersHolder); ok {
+		return holder.handlers
+	}
+	return nil
+}
+
+// storeInheritFunc sets the inheritFunc of the index, the handlers are copied on write so that the readers never lock.
+func storeInheritFunc(index int, version uint32, inherit inheritFunc) {
+	inheritHandlersMutex.Lock()
+	defer inheritHandlersMutex.Unlock()
+	old := loadInheritHandlers()
+	if inherit == nil && (index >= len(old) || old[index] == nil) {
+		return
+	}
+	length := len(old)
+	if index >= length {
+		length = index + 1
+	}
+	handlers := make([]*inheritHandler, length)
+	copy(handlers, old)
+	if inherit == nil {
+		handlers[index] = nil
+	} else {
+		handlers[index] = &inheritHandler{version: version, inherit: inherit}
+	}
+	inheritHandlers.Store(&inheritHandlersHolder{handlers: handlers})
+}
+
+// removeInheritFunc clears the inheritFunc of the index if it is owned by the version.
+func removeInheritFunc(index int, version uint32) {
+	handlers := loadInheritHandlers()
+	if index < len(handlers) && handlers[index] != nil && handlers[index].version == version {
+		storeInheritFunc(index, version, nil)
+	}
+}
+
+// inheritEntry returns the entry of the child, the Cloneable values are cloned if no inheritFunc is set.
+func inheritEntry(handlers []*inheritHandler, index int, version uint32, e entry) entry {
+	if e == unset {
+		return e
+	}
+	if index < len(handlers) {
+		if handler := handlers[index]; handler != nil && handler.version == version {
+			return handler.inherit(e)
+		}
+	}
+	return cloneValue(e)
+}
+
+// cloneEntry returns a copy of the entry if the ThreadLocal inherits the Cloneable values by clone, the other policies have been applied when inherited.
+func cloneEntry(handlers []*inheritHandler, index int, version uint32, e entry) entry {
+	if e == unset {
+		return e
+	}
+	if index < len(handlers) {
+		if handler := handlers[index]; handler != nil && handler.version == version {
+			return e
+		}
+	}
+	return cloneValue(e)
+}
+
+func cloneValue(e entry) entry {
+	if c, ok := entryAssert[Cloneable](e); ok && !isNil(c) {
+		return entry(c.Clone())
+	}
+	return e
+}
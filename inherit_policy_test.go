@@ -0,0 +1,69 @@
+package routine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoreInheritFunc(t *testing.T) {
+	index, version := acquireInheritableThreadLocalIndex()
+	defer inheritableThreadLocalIndexes.offer(index, version)
+	//
+	storeInheritFunc(index, version, nil)
+	handlers := loadInheritHandlers()
+	assert.True(t, index >= len(handlers) || handlers[index] == nil)
+	//
+	skip := newInheritFunc(InheritableThreadLocalOptions[int]{Policy: InheritPolicySkip})
+	storeInheritFunc(index, version, skip)
+	handlers = loadInheritHandlers()
+	assert.Equal(t, version, handlers[index].version)
+	assert.NotNil(t, handlers[index].inherit)
+	//
+	removeInheritFunc(index, version+1)
+	assert.NotNil(t, loadInheritHandlers()[index])
+	removeInheritFunc(index, version)
+	assert.Nil(t, loadInheritHandlers()[index])
+	assert.NotNil(t, handlers[index])
+}
+
+func TestInheritEntry(t *testing.T) {
+	value := &personCloneable{Id: 1, Name: "Tim"}
+	assert.Same(t, unset, inheritEntry(nil, 0, 0, unset))
+	assert.Equal(t, "hello", inheritEntry(nil, 0, 0, "hello"))
+	assert.NotSame(t, value, inheritEntry(nil, 0, 0, value))
+	assert.Nil(t, inheritEntry(nil, 0, 0, (*personCloneable)(nil)))
+	//
+	share := newInheritFunc(InheritableThreadLocalOptions[*personCloneable]{Policy: InheritPolicyShare})
+	handlers := []*inheritHandler{nil, {version: 1, inherit: share}}
+	assert.Same(t, value, inheritEntry(handlers, 1, 1, value))
+	assert.NotSame(t, value, inheritEntry(handlers, 1, 0, value))
+	assert.NotSame(t, value, inheritEntry(handlers, 0, 0, value))
+}
+
+func TestCloneEntry(t *testing.T) {
+	value := &personCloneable{Id: 1, Name: "Tim"}
+	assert.Same(t, unset, cloneEntry(nil, 0, 0, unset))
+	assert.Equal(t, "hello", cloneEntry(nil, 0, 0, "hello"))
+	assert.NotSame(t, value, cloneEntry(nil, 0, 0, value))
+	assert.Equal(t, *value, *cloneEntry(nil, 0, 0, value).(*personCloneable))
+	//
+	childValue := newInheritFunc(InheritableThreadLocalOptions[*personCloneable]{Policy: InheritPolicyChildValue, ChildValue: func(parent *personCloneable) *personCloneable {
+		panic("should not be called")
+	}})
+	handlers := []*inheritHandler{nil, {version: 1, inherit: childValue}}
+	assert.Same(t, value, cloneEntry(handlers, 1, 1, value))
+	assert.NotSame(t, value, cloneEntry(handlers, 1, 0, value))
+	assert.NotSame(t, value, cloneEntry(handlers, 0, 0, value))
+}
+
+func TestNewInheritFunc(t *testing.T) {
+	assert.Nil(t, newInheritFunc(InheritableThreadLocalOptions[int]{}))
+	assert.Equal(t, 1, newInheritFunc(InheritableThreadLocalOptions[int]{Policy: InheritPolicyShare})(1))
+	assert.Same(t, unset, newInheritFunc(InheritableThreadLocalOptions[int]{Policy: InheritPolicySkip})(1))
+	childValue := newInheritFunc(InheritableThreadLocalOptions[int]{Policy: InheritPolicyChildValue, ChildValue: func(parent int) int {
+		return parent * 2
+	}})
+	assert.Equal(t, 4, childValue(2))
+	assert.Equal(t, 0, childValue(nil))
+}
@@ -0,0 +1,118 @@
+package routine
+
+import (
+	"runtime"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	leakStackDepth        = 32
+	threadLocalFuncPrefix = "github.com/timandy/routine.(*threadLocal["
+)
+
+type leakHandlerHolder struct {
+	handler LeakHandler
+}
+
+var (
+	globalLeakHandler      atomic.Value
+	leakTrackerThreadLocal = NewThreadLocal[*leakTracker]().(*threadLocal[*leakTracker])
+)
+
+func loadLeakHandler() LeakHandler {
+	if holder, ok := globalLeakHandler.Load().(*leakHandlerHolder); ok {
+		return holder.handler
+	}
+	return nil
+}
+
+func storeLeakHandler(handler LeakHandler) {
+	globalLeakHandler.Store(&leakHandlerHolder{handler: handler})
+}
+
+type leakRecord struct {
+	name    string
+	version uint32
+	stack   []uintptr
+}
+
+// leakTracker records the non-inheritable ThreadLocals set during a unit of work.
+type leakTracker struct {
+	records map[int]*leakRecord
+}
+
+func (tracker *leakTracker) record(index int, version uint32, name string, stack []uintptr) {
+	if tracker.records == nil {
+		tracker.records = map[int]*leakRecord{}
+	}
+	tracker.records[index] = &leakRecord{name: name, version: version, stack: stack}
+}
+
+// report invokes the handler for each recorded value which is still present in the map.
+func (tracker *leakTracker) report(mp *threadLocalMap, handler LeakHandler) {
+	indexes := make([]int, 0, len(tracker.records))
+	for index := range tracker.records {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	for _, index := range indexes {
+		r := tracker.records[index]
+		if value, ok := lookupEntry(mp, index, r.version); ok {
+			handler(Leak{Goid: Goid(), Name: r.name, Value: value, Frames: leakFrames(r.stack)})
+		}
+	}
+}
+
+// beginLeakDetection starts a unit of work in the current goroutine and returns a function to end it.
+//
+//go:norace
+func beginLeakDetection() func() {
+	handler := loadLeakHandler()
+	if handler == nil {
+		return func() {}
+	}
+	t := currentThread(true)
+	tracker := &leakTracker{}
+	previous, hasPrevious := leakTrackerThreadLocal.getValue(t)
+	leakTrackerThreadLocal.setValue(t, tracker)
+	return func() {
+		// the thread may be replaced when the pprof labels changed
+		t := currentThread(true)
+		if hasPrevious {
+			leakTrackerThreadLocal.setValue(t, previous)
+		} else if mp := t.threadLocals; mp != nil {
+			mp.remove(leakTrackerThreadLocal.index, leakTrackerThreadLocal.version)
+		}
+		tracker.report(t.threadLocals, handler)
+	}
+}
+
+// trackThreadLocal records the stack of the caller which sets the non-inheritable ThreadLocal if the leak detection is enabled.
+//
+//go:norace
+func trackThreadLocal(t *thread, index int, version uint32, name string) {
+	if loadLeakHandler() == nil || index == leakTrackerThreadLocal.index {
+		return
+	}
+	tracker, ok := leakTrackerThreadLocal.getValue(t)
+	if !ok || tracker == nil {
+		return
+	}
+	tracker.record(index, version, name, captureStackTrace(2, leakStackDepth))
+}
+
+// leakFrames converts the stack to frames, the leading frames of the ThreadLocal methods are dropped.
+func leakFrames(stack []uintptr) []Frame {
+	var frames []Frame
+	callers := runtime.CallersFrames(stack)
+	for more := len(stack) > 0; more; {
+		var frame runtime.Frame
+		frame, more = callers.Next()
+		if len(frames) > 0 || !strings.HasPrefix(frame.Function, threadLocalFuncPrefix) {
+			frames = append(frames, newFrame(frame, false))
+		}
+	}
+	return frames
+}
@@ -0,0 +1,73 @@
+package routine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeakTracker(t *testing.T) {
+	tracker := &leakTracker{}
+	tracker.record(2, 0, "b", nil)
+	tracker.record(1, 0, "a", nil)
+	tracker.record(3, 1, "c", nil)
+	tracker.record(4, 0, "d", nil)
+	mp := &threadLocalMap{}
+	mp.set(1, 0, "v1")
+	mp.set(2, 0, "v2")
+	mp.set(3, 0, "v3")
+	var leaks []Leak
+	tracker.report(mp, func(leak Leak) {
+		leaks = append(leaks, leak)
+	})
+	assert.Equal(t, []Leak{{Goid: Goid(), Name: "a", Value: "v1"}, {Goid: Goid(), Name: "b", Value: "v2"}}, leaks)
+}
+
+func TestBeginLeakDetection(t *testing.T) {
+	task := GoWait(func(token CancelToken) {
+		beginLeakDetection()()
+		assert.False(t, leakTrackerThreadLocal.IsSet())
+		//
+		SetLeakHandler(func(leak Leak) {})
+		defer SetLeakHandler(nil)
+		end := beginLeakDetection()
+		tracker := leakTrackerThreadLocal.Get()
+		assert.NotNil(t, tracker)
+		end2 := beginLeakDetection()
+		assert.NotSame(t, tracker, leakTrackerThreadLocal.Get())
+		end2()
+		assert.Same(t, tracker, leakTrackerThreadLocal.Get())
+		end()
+		assert.False(t, leakTrackerThreadLocal.IsSet())
+		assert.Empty(t, tracker.records)
+	})
+	task.Get()
+}
+
+func TestTrackThreadLocal(t *testing.T) {
+	task := GoWait(func(token CancelToken) {
+		tls := NewThreadLocal[string]().(*threadLocal[string])
+		trackThreadLocal(currentThread(true), tls.index, tls.version, "")
+		//
+		SetLeakHandler(func(leak Leak) {})
+		defer SetLeakHandler(nil)
+		trackThreadLocal(currentThread(true), tls.index, tls.version, "")
+		assert.False(t, leakTrackerThreadLocal.IsSet())
+		//
+		defer beginLeakDetection()()
+		tracker := leakTrackerThreadLocal.Get()
+		tls.Set("value")
+		assert.Len(t, tracker.records, 1)
+		assert.Equal(t, tls.version, tracker.records[tls.index].version)
+	})
+	task.Get()
+}
+
+func TestLeakFrames(t *testing.T) {
+	assert.Nil(t, leakFrames(nil))
+	//
+	frames := leakFrames(captureStackTrace(0, 1))
+	assert.Len(t, frames, 1)
+	assert.Equal(t, "github.com/timandy/routine.TestLeakFrames", frames[0].Function)
+	assert.Equal(t, "github.com/timandy/routine", frames[0].Package)
+}
@@ -0,0 +1,60 @@
+package routine
+
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+)
+
+type uncaughtPanicHandlerHolder struct {
+	handler UncaughtPanicHandler
+}
+
+var (
+	globalUncaughtPanicHandler atomic.Value
+	crashOnUncaughtPanic       int32
+)
+
+func loadUncaughtPanicHandler() UncaughtPanicHandler {
+	if holder, ok := globalUncaughtPanicHandler.Load().(*uncaughtPanicHandlerHolder); ok {
+		return holder.handler
+	}
+	return defaultUncaughtPanicHandler
+}
+
+func storeUncaughtPanicHandler(handler UncaughtPanicHandler) {
+	globalUncaughtPanicHandler.Store(&uncaughtPanicHandlerHolder{handler: handler})
+}
+
+func loadCrashOnUncaughtPanic() bool {
+	return atomic.LoadInt32(&crashOnUncaughtPanic) == 1
+}
+
+func storeCrashOnUncaughtPanic(crash bool) {
+	var value int32
+	if crash {
+		value = 1
+	}
+	atomic.StoreInt32(&crashOnUncaughtPanic, value)
+}
+
+func defaultUncaughtPanicHandler(goid uint64, err RuntimeError) {
+	_, _ = fmt.Fprintln(os.Stderr, err.Error())
+}
+
+// handleUncaughtPanic invokes the handler or the global UncaughtPanicHandler if the handler is nil.
+func handleUncaughtPanic(handler UncaughtPanicHandler, err RuntimeError) {
+	if handler == nil {
+		handler = loadUncaughtPanicHandler()
+	}
+	handler(Goid(), err)
+	if loadCrashOnUncaughtPanic() {
+		// the panic raised in the current goroutine will be recovered by the task or the caller of the callbacks,
+		// the process keeps running until the new goroutine is scheduled
+		go crash(err)
+	}
+}
+
+func crash(err RuntimeError) {
+	panic(err)
+}
@@ -0,0 +1,67 @@
+package routine
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUncaughtPanicHandler_Store(t *testing.T) {
+	defer storeUncaughtPanicHandler(defaultUncaughtPanicHandler)
+	count := 0
+	storeUncaughtPanicHandler(func(goid uint64, err RuntimeError) {
+		count++
+	})
+	loadUncaughtPanicHandler()(0, nil)
+	assert.Equal(t, 1, count)
+}
+
+func TestCrashOnUncaughtPanic_Store(t *testing.T) {
+	defer storeCrashOnUncaughtPanic(false)
+	assert.False(t, loadCrashOnUncaughtPanic())
+	storeCrashOnUncaughtPanic(true)
+	assert.True(t, loadCrashOnUncaughtPanic())
+	storeCrashOnUncaughtPanic(false)
+	assert.False(t, loadCrashOnUncaughtPanic())
+}
+
+func TestDefaultUncaughtPanicHandler(t *testing.T) {
+	tracker := NewFileTracker(os.Stderr)
+	tracker.Begin()
+	defer tracker.End()
+	//
+	err := NewRuntimeError("error")
+	defaultUncaughtPanicHandler(Goid(), err)
+	assert.Equal(t, err.Error()+newLine, tracker.Value())
+}
+
+func TestHandleUncaughtPanic(t *testing.T) {
+	defer SetUncaughtPanicHandler(nil)
+	var goid uint64
+	SetUncaughtPanicHandler(func(g uint64, err RuntimeError) {
+		goid = g
+	})
+	handleUncaughtPanic(nil, NewRuntimeError(nil))
+	assert.Equal(t, Goid(), goid)
+	//
+	run := false
+	handleUncaughtPanic(func(g uint64, err RuntimeError) {
+		run = true
+	}, NewRuntimeError(nil))
+	assert.True(t, run)
+}
+
+func TestHandleUncaughtPanic_OnComplete(t *testing.T) {
+	defer SetUncaughtPanicHandler(nil)
+	var err RuntimeError
+	SetUncaughtPanicHandler(func(goid uint64, e RuntimeError) {
+		err = e
+	})
+	task := NewFutureTask[int](func(task FutureTask[int]) int { return 1 })
+	task.OnComplete(func(result int, e RuntimeError) {
+		panic("callback error")
+	})
+	task.Run()
+	assert.Equal(t, "callback error", err.Message())
+}
@@ -0,0 +1,194 @@
+package routine
+
+import (
+	"context"
+	"runtime/pprof"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// loadLabels returns the pprof labels of the current goroutine.
+// The labels of g points to a labelMap of runtime/pprof or a thread whose first field is the labelMap, so it can be read in the same way.
+//
+//go:norace
+func loadLabels() labelMap {
+	label := getg().getLabels()
+	if label == nil {
+		return nil
+	}
+	return *(*labelMap)(label)
+}
+
+// contextLabels returns the pprof labels stored in the context by pprof.WithLabels.
+func contextLabels(ctx context.Context) map[string]string {
+	labels := map[string]string{}
+	pprof.ForLabels(ctx, func(key, value string) bool {
+		labels[key] = value
+		return true
+	})
+	return labels
+}
+
+// newLabelPointer returns the pointer which can be stored into the labels of g directly.
+func newLabelPointer(mp labelMap) unsafe.Pointer {
+	if mp == nil {
+		return nil
+	}
+	return unsafe.Pointer(&mp)
+}
+
+type labelMirrorKey struct {
+	inheritable bool
+	index       int
+}
+
+type labelMirror struct {
+	key     string
+	version uint32
+}
+
+type labelMirrorsHolder struct {
+	mirrors map[labelMirrorKey]*labelMirror
+}
+
+var (
+	labelMirrors      atomic.Value
+	labelMirrorsMutex sync.Mutex
+)
+
+func loadLabelMirrors() map[labelMirrorKey]*labelMirror {
+	if holder, ok := labelMirrors.Load().(*labelMirrorsHolder); ok {
+		return holder.mirrors
+	}
+	return nil
+}
+
+// updateLabelMirrors copies the mirrors, updates the copy by the function and stores it, so that the readers never lock.
+func updateLabelMirrors(fun func(mirrors map[labelMirrorKey]*labelMirror)) {
+	labelMirrorsMutex.Lock()
+	defer labelMirrorsMutex.Unlock()
+	old := loadLabelMirrors()
+	mirrors := make(map[labelMirrorKey]*labelMirror, len(old)+1)
+	for k, v := range old {
+		mirrors[k] = v
+	}
+	fun(mirrors)
+	labelMirrors.Store(&labelMirrorsHolder{mirrors: mirrors})
+}
+
+func addLabelMirror(key string, inheritable bool, index int, version uint32) {
+	updateLabelMirrors(func(mirrors map[labelMirrorKey]*labelMirror) {
+		for k, mirror := range mirrors {
+			if mirror.key == key {
+				delete(mirrors, k)
+			}
+		}
+		mirrors[labelMirrorKey{inheritable: inheritable, index: index}] = &labelMirror{key: key, version: version}
+	})
+}
+
+func removeLabelMirror(key string) {
+	updateLabelMirrors(func(mirrors map[labelMirrorKey]*labelMirror) {
+		for k, mirror := range mirrors {
+			if mirror.key == key {
+				delete(mirrors, k)
+			}
+		}
+	})
+}
+
+// removeThreadLocalLabelMirror removes the mirror of the closed ThreadLocal.
+func removeThreadLocalLabelMirror(inheritable bool, index int, version uint32) {
+	k := labelMirrorKey{inheritable: inheritable, index: index}
+	if mirror := loadLabelMirrors()[k]; mirror == nil || mirror.version != version {
+		return
+	}
+	updateLabelMirrors(func(mirrors map[labelMirrorKey]*labelMirror) {
+		if mirror := mirrors[k]; mirror != nil && mirror.version == version {
+			delete(mirrors, k)
+		}
+	})
+}
+
+// mirrorThreadLocal copies the value of the ThreadLocal into the pprof labels if it is mirrored, the label is deleted if the value is unset.
+func mirrorThreadLocal(inheritable bool, index int, version uint32, value entry) {
+	mirrors := loadLabelMirrors()
+	if len(mirrors) == 0 {
+		return
+	}
+	mirror := mirrors[labelMirrorKey{inheritable: inheritable, index: index}]
+	if mirror == nil || mirror.version != version {
+		return
+	}
+	// the labels and the thread are rebuilt only if the label changed
+	mp := loadLabels()
+	current, ok := mp.get(mirror.key)
+	if value == unset {
+		if !ok {
+			return
+		}
+		labels := mp.toMap()
+		delete(labels, mirror.key)
+		storeLabels(newLabelMap(labels))
+		return
+	}
+	str := entryValue[string](value)
+	if ok && current == str {
+		return
+	}
+	labels := mp.toMap()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[mirror.key] = str
+	storeLabels(newLabelMap(labels))
+}
+
+// syncLabelMirrors updates the mirrored labels by the values in the maps of the thread, it must be called after the maps of the current thread replaced.
+// The labels and the thread are rebuilt only if any mirrored label changed.
+func syncLabelMirrors(t *thread) {
+	mirrors := loadLabelMirrors()
+	if len(mirrors) == 0 {
+		return
+	}
+	mp := loadLabels()
+	var labels map[string]string
+	for k, mirror := range mirrors {
+		threadLocals := t.threadLocals
+		if k.inheritable {
+			threadLocals = t.inheritableThreadLocals
+		}
+		value, ok := lookupEntry(threadLocals, k.index, mirror.version)
+		current, exists := mp.get(mirror.key)
+		if !ok {
+			if !exists {
+				continue
+			}
+			if labels == nil {
+				labels = mp.toMap()
+			}
+			delete(labels, mirror.key)
+			continue
+		}
+		str := entryValue[string](value)
+		if exists && current == str {
+			continue
+		}
+		if labels == nil {
+			labels = mp.toMap()
+			if labels == nil {
+				labels = map[string]string{}
+			}
+		}
+		labels[mirror.key] = str
+	}
+	if labels != nil {
+		storeLabels(newLabelMap(labels))
+	}
+}
+
+// hasLabelMirrors returns true if any ThreadLocal is mirrored.
+func hasLabelMirrors() bool {
+	return len(loadLabelMirrors()) > 0
+}
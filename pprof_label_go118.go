@@ -3,3 +3,33 @@
 package routine
 
 type labelMap map[string]string
+
+// toMap returns a copy of the labels.
+func (mp labelMap) toMap() map[string]string {
+	if len(mp) == 0 {
+		return nil
+	}
+	labels := make(map[string]string, len(mp))
+	for key, value := range mp {
+		labels[key] = value
+	}
+	return labels
+}
+
+// get returns the value of the key and whether the key exists.
+func (mp labelMap) get(key string) (string, bool) {
+	value, ok := mp[key]
+	return value, ok
+}
+
+// newLabelMap returns the labelMap in the layout of runtime/pprof, returns nil if the labels is empty.
+func newLabelMap(labels map[string]string) labelMap {
+	if len(labels) == 0 {
+		return nil
+	}
+	mp := make(labelMap, len(labels))
+	for key, value := range labels {
+		mp[key] = value
+	}
+	return mp
+}
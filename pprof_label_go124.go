@@ -2,4 +2,55 @@
 
 package routine
 
+import (
+	"sort"
+	"unsafe"
+)
+
 type labelMap []any
+
+// label is the same as the label of runtime/pprof, the labelMap is a slice of label sorted by key since go1.24.
+type label struct {
+	key   string
+	value string
+}
+
+// toMap returns a copy of the labels.
+func (mp labelMap) toMap() map[string]string {
+	list := *(*[]label)(unsafe.Pointer(&mp))
+	if len(list) == 0 {
+		return nil
+	}
+	labels := make(map[string]string, len(list))
+	for _, l := range list {
+		labels[l.key] = l.value
+	}
+	return labels
+}
+
+// get returns the value of the key and whether the key exists.
+func (mp labelMap) get(key string) (string, bool) {
+	list := *(*[]label)(unsafe.Pointer(&mp))
+	i := sort.Search(len(list), func(i int) bool {
+		return list[i].key >= key
+	})
+	if i < len(list) && list[i].key == key {
+		return list[i].value, true
+	}
+	return "", false
+}
+
+// newLabelMap returns the labelMap in the layout of runtime/pprof, returns nil if the labels is empty.
+func newLabelMap(labels map[string]string) labelMap {
+	if len(labels) == 0 {
+		return nil
+	}
+	list := make([]label, 0, len(labels))
+	for key, value := range labels {
+		list = append(list, label{key: key, value: value})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].key < list[j].key
+	})
+	return *(*labelMap)(unsafe.Pointer(&list))
+}
@@ -1,6 +1,8 @@
 package routine
 
 import (
+	"context"
+	"runtime/pprof"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -21,3 +23,128 @@ func TestLabelMap_Empty(t *testing.T) {
 	labels = labelMap{}
 	assert.Empty(t, labels)
 }
+
+func TestLabelMap_ToMap(t *testing.T) {
+	var labels labelMap
+	assert.Nil(t, labels.toMap())
+	//
+	labels = newLabelMap(map[string]string{"b": "2", "a": "1"})
+	assert.Equal(t, map[string]string{"a": "1", "b": "2"}, labels.toMap())
+	//
+	assert.Nil(t, newLabelMap(nil))
+	assert.Nil(t, newLabelMap(map[string]string{}))
+}
+
+func TestLabelMap_Get(t *testing.T) {
+	var labels labelMap
+	_, ok := labels.get("a")
+	assert.False(t, ok)
+	//
+	labels = newLabelMap(map[string]string{"c": "3", "a": "1", "b": ""})
+	value, ok := labels.get("a")
+	assert.True(t, ok)
+	assert.Equal(t, "1", value)
+	value, ok = labels.get("b")
+	assert.True(t, ok)
+	assert.Equal(t, "", value)
+	value, ok = labels.get("c")
+	assert.True(t, ok)
+	assert.Equal(t, "3", value)
+	_, ok = labels.get("d")
+	assert.False(t, ok)
+	_, ok = labels.get("")
+	assert.False(t, ok)
+}
+
+func TestNewLabelMap_Layout(t *testing.T) {
+	ctx := pprof.WithLabels(context.Background(), pprof.Labels("b", "2", "a", "1"))
+	pprof.SetGoroutineLabels(ctx)
+	defer pprof.SetGoroutineLabels(context.Background())
+	assert.Equal(t, map[string]string{"a": "1", "b": "2"}, loadLabels().toMap())
+
+}
+
+func TestLoadLabels(t *testing.T) {
+	task := GoWait(func(token CancelToken) {
+		assert.Nil(t, loadLabels())
+		storeLabels(newLabelMap(map[string]string{"key": "value"}))
+		assert.Equal(t, map[string]string{"key": "value"}, loadLabels().toMap())
+	})
+	task.Get()
+}
+
+func TestContextLabels(t *testing.T) {
+	assert.Empty(t, contextLabels(context.Background()))
+	ctx := pprof.WithLabels(context.Background(), pprof.Labels("key", "value"))
+	assert.Equal(t, map[string]string{"key": "value"}, contextLabels(ctx))
+}
+
+func TestLabelMirrors(t *testing.T) {
+	addLabelMirror("TestLabelMirrors", false, 100, 1)
+	assert.Equal(t, &labelMirror{key: "TestLabelMirrors", version: 1}, loadLabelMirrors()[labelMirrorKey{index: 100}])
+	assert.True(t, hasLabelMirrors())
+	//
+	addLabelMirror("TestLabelMirrors", true, 101, 0)
+	assert.Nil(t, loadLabelMirrors()[labelMirrorKey{index: 100}])
+	assert.NotNil(t, loadLabelMirrors()[labelMirrorKey{inheritable: true, index: 101}])
+	//
+	removeThreadLocalLabelMirror(true, 101, 1)
+	assert.NotNil(t, loadLabelMirrors()[labelMirrorKey{inheritable: true, index: 101}])
+	removeThreadLocalLabelMirror(true, 101, 0)
+	assert.Nil(t, loadLabelMirrors()[labelMirrorKey{inheritable: true, index: 101}])
+	//
+	addLabelMirror("TestLabelMirrors", false, 100, 1)
+	removeLabelMirror("TestLabelMirrors")
+	assert.Nil(t, loadLabelMirrors()[labelMirrorKey{index: 100}])
+}
+
+func TestSyncLabelMirrors(t *testing.T) {
+	task := GoWait(func(token CancelToken) {
+		addLabelMirror("TestSyncLabelMirrors", true, 100, 1)
+		defer removeLabelMirror("TestSyncLabelMirrors")
+		SetLabels(map[string]string{"key": "value"})
+		th := currentThread(true)
+		syncLabelMirrors(th)
+		assert.Same(t, th, currentThread(false))
+		//
+		mp := &threadLocalMap{}
+		mp.set(100, 1, entry("Hello"))
+		th.inheritableThreadLocals = mp
+		syncLabelMirrors(th)
+		assert.Equal(t, map[string]string{"key": "value", "TestSyncLabelMirrors": "Hello"}, Labels())
+		th = currentThread(false)
+		assert.Same(t, mp, th.inheritableThreadLocals)
+		syncLabelMirrors(th)
+		assert.Same(t, th, currentThread(false))
+		//
+		th.inheritableThreadLocals = nil
+		syncLabelMirrors(th)
+		assert.Equal(t, map[string]string{"key": "value"}, Labels())
+	})
+	task.Get()
+}
+
+func TestMirrorThreadLocal(t *testing.T) {
+	task := GoWait(func(token CancelToken) {
+		mirrorThreadLocal(false, 100, 0, entry("value"))
+		assert.Nil(t, loadLabels())
+		//
+		addLabelMirror("TestMirrorThreadLocal", false, 100, 1)
+		defer removeLabelMirror("TestMirrorThreadLocal")
+		mirrorThreadLocal(false, 100, 0, entry("value"))
+		assert.Nil(t, loadLabels())
+		mirrorThreadLocal(false, 100, 1, entry("value"))
+		assert.Equal(t, map[string]string{"TestMirrorThreadLocal": "value"}, loadLabels().toMap())
+		label := getg().getLabels()
+		mirrorThreadLocal(false, 100, 1, entry("value"))
+		assert.Equal(t, label, getg().getLabels())
+		mirrorThreadLocal(false, 100, 1, entry("value2"))
+		assert.NotEqual(t, label, getg().getLabels())
+		assert.Equal(t, map[string]string{"TestMirrorThreadLocal": "value2"}, loadLabels().toMap())
+		mirrorThreadLocal(false, 100, 1, unset)
+		assert.Nil(t, loadLabels())
+		mirrorThreadLocal(false, 100, 1, unset)
+		assert.Nil(t, loadLabels())
+	})
+	task.Get()
+}
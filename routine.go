@@ -1,65 +1,110 @@
 package routine
 
-import "fmt"
-
 type inheritedTask struct {
-	context  *threadLocalMap
-	function Runnable
+	context   *threadLocalMap
+	ancestors []Ancestor
+	function  Runnable
+	handler   UncaughtPanicHandler
 }
 
 //go:norace
 func (it inheritedTask) run(task FutureTask[any]) any {
+	// restore
+	defer restoreInheritedMap(it.context)()
+	// trace
+	goid := Goid()
+	defer swapAncestors(goid, swapAncestors(goid, it.ancestors))
 	// catch
 	defer func() {
 		if cause := recover(); cause != nil {
 			task.Fail(cause)
 			if err := task.(*futureTask[any]).error; err != nil {
-				fmt.Println(err.Error())
+				handleUncaughtPanic(it.handler, err)
 			}
 		}
 	}()
-	// restore
-	defer restoreInheritedMap(it.context)()
+	// detect
+	defer beginLeakDetection()()
 	// exec
 	it.function()
 	return nil
 }
 
 type inheritedWaitTask struct {
-	context  *threadLocalMap
-	function CancelRunnable
+	context   *threadLocalMap
+	ancestors []Ancestor
+	function  CancelRunnable
 }
 
 //go:norace
 func (iwt inheritedWaitTask) run(task FutureTask[any]) any {
+	// restore
+	defer restoreInheritedMap(iwt.context)()
+	// trace
+	goid := Goid()
+	defer swapAncestors(goid, swapAncestors(goid, iwt.ancestors))
 	// catch
 	defer func() {
 		if cause := recover(); cause != nil {
 			task.Fail(cause)
 		}
 	}()
-	// restore
-	defer restoreInheritedMap(iwt.context)()
+	// detect
+	defer beginLeakDetection()()
+	// watch
+	cancelWithContext(task)
 	// exec
 	iwt.function(task)
 	return nil
 }
 
 type inheritedWaitResultTask[TResult any] struct {
-	context  *threadLocalMap
-	function CancelCallable[TResult]
+	context   *threadLocalMap
+	ancestors []Ancestor
+	function  CancelCallable[TResult]
 }
 
 //go:norace
 func (iwrt inheritedWaitResultTask[TResult]) run(task FutureTask[TResult]) TResult {
+	// restore
+	defer restoreInheritedMap(iwrt.context)()
+	// trace
+	goid := Goid()
+	defer swapAncestors(goid, swapAncestors(goid, iwrt.ancestors))
 	// catch
 	defer func() {
 		if cause := recover(); cause != nil {
 			task.Fail(cause)
 		}
 	}()
-	// restore
-	defer restoreInheritedMap(iwrt.context)()
+	// detect
+	defer beginLeakDetection()()
+	// watch
+	cancelWithContext(task)
 	// exec
 	return iwrt.function(task)
 }
+
+// wrapTask creates the task of WrapTask, skip is the number of frames to skip before recording the call site, 0 identifying the caller of wrapTask.
+func wrapTask(fun Runnable, handler UncaughtPanicHandler, skip int) FutureTask[any] {
+	ctx := createInheritedMap()
+	ancestors := newAncestors(skip + 1)
+	callable := inheritedTask{context: ctx, ancestors: ancestors, function: fun, handler: handler}.run
+	return NewFutureTask[any](callable)
+}
+
+// wrapWaitTask creates the task of WrapWaitTask, skip is the number of frames to skip before recording the call site, 0 identifying the caller of wrapWaitTask.
+func wrapWaitTask(fun CancelRunnable, skip int) FutureTask[any] {
+	ctx := createInheritedMap()
+	ancestors := newAncestors(skip + 1)
+	callable := inheritedWaitTask{context: ctx, ancestors: ancestors, function: fun}.run
+	return NewFutureTask[any](callable)
+}
+
+// wrapWaitResultTask creates the task of WrapWaitResultTask, skip is the number of frames to skip before recording the call site, 0 identifying the caller of wrapWaitResultTask.
+func wrapWaitResultTask[TResult any](fun CancelCallable[TResult], skip int) FutureTask[TResult] {
+	ctx := createInheritedMap()
+	ancestors := newAncestors(skip + 1)
+	callable := inheritedWaitResultTask[TResult]{context: ctx, ancestors: ancestors, function: fun}.run
+	return NewFutureTask[TResult](callable)
+}
@@ -0,0 +1,15 @@
+//go:build !go1.21
+
+package routine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetgt_ParentGoid(t *testing.T) {
+	_, found := getgt().FieldByName("parentGoid")
+	assert.False(t, found)
+	assert.Equal(t, uintptr(0), offsetParentGoid)
+}
@@ -0,0 +1,18 @@
+//go:build go1.21
+
+package routine
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetgt_ParentGoid(t *testing.T) {
+	parentGoid, found := getgt().FieldByName("parentGoid")
+	assert.True(t, found)
+	assert.Equal(t, reflect.Uint64, parentGoid.Type.Kind())
+	assert.Equal(t, parentGoid.Offset, offsetParentGoid)
+	assert.Greater(t, int(offsetParentGoid), 0)
+}
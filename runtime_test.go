@@ -47,12 +47,17 @@ func TestGetgt(t *testing.T) {
 	fmt.Println("#offsetPaniconfault:", offsetPaniconfault)
 	fmt.Println("#offsetGopc:", offsetGopc)
 	fmt.Println("#offsetLabels:", offsetLabels)
+	fmt.Println("#offsetAtomicstatus:", offsetAtomicstatus)
+	fmt.Println("#offsetStartpc:", offsetStartpc)
+	fmt.Println("#offsetParentGoid:", offsetParentGoid)
 	//
 	assert.Greater(t, numField, 20)
 	assert.Greater(t, int(offsetGoid), 0)
 	assert.Greater(t, int(offsetPaniconfault), 0)
 	assert.Greater(t, int(offsetGopc), 0)
 	assert.Greater(t, int(offsetLabels), 0)
+	assert.Greater(t, int(offsetAtomicstatus), 0)
+	assert.Greater(t, int(offsetStartpc), 0)
 	//
 	runTest(t, func() {
 		tt := getgt()
@@ -62,9 +67,25 @@ func TestGetgt(t *testing.T) {
 		assert.Equal(t, offsetPaniconfault, offset(tt, "paniconfault"))
 		assert.Equal(t, offsetGopc, offset(tt, "gopc"))
 		assert.Equal(t, offsetLabels, offset(tt, "labels"))
+		assert.Equal(t, offsetAtomicstatus, offset(tt, "atomicstatus"))
+		assert.Equal(t, offsetStartpc, offset(tt, "startpc"))
+		assert.Equal(t, offsetParentGoid, optionalOffset(tt, "parentGoid"))
 	})
 }
 
+func TestGetgt_FieldTypes(t *testing.T) {
+	gt := getgt()
+	// the atomicstatus is uint32 before go1.20 and atomic.Uint32 since go1.20
+	atomicstatus, _ := gt.FieldByName("atomicstatus")
+	assert.Equal(t, uintptr(4), atomicstatus.Type.Size())
+	startpc, _ := gt.FieldByName("startpc")
+	assert.Equal(t, reflect.Uintptr, startpc.Type.Kind())
+	gopc, _ := gt.FieldByName("gopc")
+	assert.Equal(t, reflect.Uintptr, gopc.Type.Kind())
+	goid, _ := gt.FieldByName("goid")
+	assert.Equal(t, reflect.Uint64, goid.Type.Kind())
+}
+
 func TestGetg(t *testing.T) {
 	runTest(t, func() {
 		g0 := packEface(getgt(), unsafe.Pointer(getgp()))
@@ -0,0 +1,107 @@
+package routine
+
+import "sync/atomic"
+
+type scopedValue[T any] struct {
+	index   int
+	version uint32
+	closed  int32
+}
+
+// scopedValueEntry wraps the bound value, so that the value will never be cloned when inherited.
+type scopedValueEntry[T any] struct {
+	value T
+}
+
+func (sv *scopedValue[T]) Get() T {
+	if value, ok := sv.lookup(); ok {
+		return value
+	}
+	panic("ScopedValue is not bound.")
+}
+
+func (sv *scopedValue[T]) IsBound() bool {
+	_, ok := sv.lookup()
+	return ok
+}
+
+func (sv *scopedValue[T]) OrElse(other T) T {
+	if value, ok := sv.lookup(); ok {
+		return value
+	}
+	return other
+}
+
+func (sv *scopedValue[T]) Where(value T) ScopedBinding {
+	return &scopedBinding[T]{scopedValue: sv, value: value}
+}
+
+func (sv *scopedValue[T]) Close() {
+	if atomic.CompareAndSwapInt32(&sv.closed, 0, 1) {
+		inheritableThreadLocalIndexes.offer(sv.index, sv.version)
+	}
+}
+
+//go:norace
+func (sv *scopedValue[T]) lookup() (T, bool) {
+	t := currentThread(false)
+	if t != nil && t.inheritableThreadLocals != nil {
+		if e, ok := entryAssert[*scopedValueEntry[T]](t.inheritableThreadLocals.get(sv.index, sv.version)); ok {
+			return e.value, true
+		}
+	}
+	var defaultValue T
+	return defaultValue, false
+}
+
+// bind stores the value into the current goroutine and returns a function to restore the previous binding.
+//
+//go:norace
+func (sv *scopedValue[T]) bind(value T) func() {
+	t := currentThread(true)
+	mp := t.inheritableThreadLocals
+	if mp == nil {
+		mp = &threadLocalMap{}
+		t.inheritableThreadLocals = mp
+	}
+	previous := mp.get(sv.index, sv.version)
+	mp.set(sv.index, sv.version, entry(&scopedValueEntry[T]{value: value}))
+	return func() {
+		// the map may be replaced during the function, e.g. a Snapshot restored or the pprof labels changed
+		t := currentThread(true)
+		mp := t.inheritableThreadLocals
+		if mp == nil {
+			if previous == unset {
+				return
+			}
+			mp = &threadLocalMap{}
+			t.inheritableThreadLocals = mp
+		}
+		if previous == unset {
+			mp.remove(sv.index, sv.version)
+			return
+		}
+		mp.set(sv.index, sv.version, previous)
+	}
+}
+
+type scopedBinding[T any] struct {
+	scopedValue *scopedValue[T]
+	value       T
+}
+
+func (b *scopedBinding[T]) Run(fun Runnable) {
+	if fun == nil {
+		panic("fun can not be nil.")
+	}
+	defer b.scopedValue.bind(b.value)()
+	fun()
+}
+
+func (b *scopedBinding[T]) Call(fun Callable[any]) any {
+	if fun == nil {
+		panic("fun can not be nil.")
+	}
+	defer b.scopedValue.bind(b.value)()
+	return fun()
+}
@@ -0,0 +1,50 @@
+package routine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScopedValue_Bind(t *testing.T) {
+	sv := NewScopedValue[string]().(*scopedValue[string])
+	task := GoWait(func(token CancelToken) {
+		undo := sv.bind("hello")
+		assert.NotNil(t, currentThread(false).inheritableThreadLocals)
+		value, ok := sv.lookup()
+		assert.True(t, ok)
+		assert.Equal(t, "hello", value)
+		undo()
+		_, ok = sv.lookup()
+		assert.False(t, ok)
+	})
+	task.Get()
+}
+
+func TestScopedValue_Bind_MapReplaced(t *testing.T) {
+	sv := NewScopedValue[string]().(*scopedValue[string])
+	task := GoWait(func(token CancelToken) {
+		undo := sv.bind("outer")
+		undo2 := sv.bind("inner")
+		currentThread(false).inheritableThreadLocals = nil
+		undo2()
+		assert.Equal(t, "outer", sv.Get())
+		currentThread(false).inheritableThreadLocals = nil
+		undo()
+		assert.Nil(t, currentThread(false).inheritableThreadLocals)
+	})
+	task.Get()
+}
+
+func TestScopedValue_Clone(t *testing.T) {
+	sv := NewScopedValue[*personCloneable]().(*scopedValue[*personCloneable])
+	task := GoWait(func(token CancelToken) {
+		value := &personCloneable{Id: 1, Name: "Tim"}
+		defer sv.bind(value)()
+		mp := createInheritedMap()
+		e, ok := entryAssert[*scopedValueEntry[*personCloneable]](mp.get(sv.index, sv.version))
+		assert.True(t, ok)
+		assert.Same(t, value, e.value)
+	})
+	task.Get()
+}
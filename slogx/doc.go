@@ -0,0 +1,4 @@
+// Package slogx integrates log/slog with the goroutine-local storage of routine.
+// The values of the registered ThreadLocals and the goid are attached to every record handled by the Handler.
+// The package requires go1.21 or later.
+package slogx
@@ -0,0 +1,65 @@
+//go:build go1.21
+
+package slogx
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/timandy/routine"
+)
+
+// GoidKey is the key of the goid attribute.
+const GoidKey = "goid"
+
+// HandlerOptions are options for the Handler.
+type HandlerOptions struct {
+	// OmitGoid omits the goid attribute if it is true.
+	OmitGoid bool
+}
+
+// Handler is a slog.Handler which attaches the goid and the registered ThreadLocals of the current goroutine to every record, then passes it to the next handler.
+// The attributes are qualified by the groups opened by WithGroup, the same as the attributes of the record.
+type Handler struct {
+	next slog.Handler
+	opts HandlerOptions
+}
+
+// NewHandler create a new Handler which passes the records to next, if opts is nil, the default options are used.
+func NewHandler(next slog.Handler, opts *HandlerOptions) *Handler {
+	if next == nil {
+		panic("next can not be nil.")
+	}
+	if opts == nil {
+		opts = &HandlerOptions{}
+	}
+	return &Handler{next: next, opts: *opts}
+}
+
+// Enabled reports whether the next handler handles records at the given level.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle attaches the goid and the registered ThreadLocals of the current goroutine to the record, then passes it to the next handler.
+func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
+	attrs := Attrs()
+	if !h.opts.OmitGoid {
+		attrs = append(attrs, slog.Uint64(GoidKey, routine.Goid()))
+	}
+	if len(attrs) > 0 {
+		record = record.Clone()
+		record.AddAttrs(attrs...)
+	}
+	return h.next.Handle(ctx, record)
+}
+
+// WithAttrs returns a new Handler whose next handler has the attrs.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Handler{next: h.next.WithAttrs(attrs), opts: h.opts}
+}
+
+// WithGroup returns a new Handler whose next handler has the group.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{next: h.next.WithGroup(name), opts: h.opts}
+}
@@ -0,0 +1,86 @@
+//go:build go1.21
+
+package slogx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/timandy/routine"
+)
+
+func TestNewHandler(t *testing.T) {
+	next := slog.NewJSONHandler(&bytes.Buffer{}, nil)
+	handler := NewHandler(next, nil)
+	assert.Same(t, next, handler.next)
+	assert.False(t, handler.opts.OmitGoid)
+	//
+	handler2 := NewHandler(next, &HandlerOptions{OmitGoid: true})
+	assert.True(t, handler2.opts.OmitGoid)
+	//
+	assert.Panics(t, func() {
+		NewHandler(nil, nil)
+	})
+}
+
+func TestHandler_Enabled(t *testing.T) {
+	next := slog.NewJSONHandler(&bytes.Buffer{}, &slog.HandlerOptions{Level: slog.LevelWarn})
+	handler := NewHandler(next, nil)
+	assert.False(t, handler.Enabled(context.Background(), slog.LevelInfo))
+	assert.True(t, handler.Enabled(context.Background(), slog.LevelError))
+}
+
+func TestHandler_Handle(t *testing.T) {
+	tls := routine.NewInheritableThreadLocal[string]()
+	Register("request_id", tls)
+	defer Unregister("request_id")
+	buffer := &bytes.Buffer{}
+	logger := slog.New(NewHandler(slog.NewJSONHandler(buffer, nil), nil))
+	tls.Set("abc")
+	task := routine.GoWait(func(token routine.CancelToken) {
+		logger.Info("Hello", "key", "value")
+	})
+	task.Get()
+	var value map[string]any
+	assert.Nil(t, json.Unmarshal(buffer.Bytes(), &value))
+	assert.Equal(t, "Hello", value["msg"])
+	assert.Equal(t, "value", value["key"])
+	assert.Equal(t, "abc", value["request_id"])
+	assert.NotEqual(t, float64(routine.Goid()), value[GoidKey])
+	assert.Greater(t, value[GoidKey], float64(0))
+	//
+	buffer.Reset()
+	tls.Remove()
+	logger2 := slog.New(NewHandler(slog.NewJSONHandler(buffer, nil), &HandlerOptions{OmitGoid: true}))
+	logger2.Info("World")
+	var value2 map[string]any
+	assert.Nil(t, json.Unmarshal(buffer.Bytes(), &value2))
+	assert.Equal(t, "World", value2["msg"])
+	assert.NotContains(t, value2, "request_id")
+	assert.NotContains(t, value2, GoidKey)
+}
+
+func TestHandler_WithAttrs(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	logger := slog.New(NewHandler(slog.NewJSONHandler(buffer, nil), nil)).With("key", "value")
+	logger.Info("Hello")
+	var value map[string]any
+	assert.Nil(t, json.Unmarshal(buffer.Bytes(), &value))
+	assert.Equal(t, "value", value["key"])
+	assert.Equal(t, float64(routine.Goid()), value[GoidKey])
+}
+
+func TestHandler_WithGroup(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	logger := slog.New(NewHandler(slog.NewJSONHandler(buffer, nil), nil)).WithGroup("group")
+	logger.Info("Hello", "key", "value")
+	var value map[string]any
+	assert.Nil(t, json.Unmarshal(buffer.Bytes(), &value))
+	group := value["group"].(map[string]any)
+	assert.Equal(t, "value", group["key"])
+	assert.Equal(t, float64(routine.Goid()), group[GoidKey])
+}
@@ -0,0 +1,73 @@
+//go:build go1.21
+
+package slogx
+
+import (
+	"log/slog"
+	"sync"
+
+	"github.com/timandy/routine"
+)
+
+type attrGetter func() (slog.Value, bool)
+
+type registration struct {
+	key    string
+	getter attrGetter
+}
+
+var (
+	registryMutex sync.RWMutex
+	registrations []registration
+)
+
+// Register registers the ThreadLocal whose value will be attached to every record as an attribute with the key.
+// The attribute is omitted if the value was not set in the current goroutine, the previous registration with the same key will be replaced.
+func Register[T any](key string, tls routine.ThreadLocal[T]) {
+	if len(key) == 0 {
+		panic("key can not be empty.")
+	}
+	if tls == nil {
+		panic("tls can not be nil.")
+	}
+	getter := func() (slog.Value, bool) {
+		if !tls.IsSet() {
+			return slog.Value{}, false
+		}
+		return slog.AnyValue(tls.Get()), true
+	}
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	for i := range registrations {
+		if registrations[i].key == key {
+			registrations[i].getter = getter
+			return
+		}
+	}
+	registrations = append(registrations, registration{key: key, getter: getter})
+}
+
+// Unregister removes the registration with the key.
+func Unregister(key string) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	for i := range registrations {
+		if registrations[i].key == key {
+			registrations = append(registrations[:i:i], registrations[i+1:]...)
+			return
+		}
+	}
+}
+
+// Attrs returns the attributes of the registered ThreadLocals which were set in the current goroutine.
+func Attrs() []slog.Attr {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	attrs := make([]slog.Attr, 0, len(registrations))
+	for _, reg := range registrations {
+		if value, ok := reg.getter(); ok {
+			attrs = append(attrs, slog.Attr{Key: reg.key, Value: value})
+		}
+	}
+	return attrs
+}
@@ -0,0 +1,52 @@
+//go:build go1.21
+
+package slogx
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/timandy/routine"
+)
+
+func TestRegister(t *testing.T) {
+	tls := routine.NewThreadLocal[string]()
+	tls2 := routine.NewInheritableThreadLocal[int]()
+	Register("request_id", tls)
+	Register("tenant", tls2)
+	defer Unregister("request_id")
+	defer Unregister("tenant")
+	assert.Empty(t, Attrs())
+	//
+	tls.Set("abc")
+	assert.Equal(t, []slog.Attr{slog.String("request_id", "abc")}, Attrs())
+	tls2.Set(1)
+	assert.Equal(t, []slog.Attr{slog.String("request_id", "abc"), slog.Int("tenant", 1)}, Attrs())
+	//
+	tls3 := routine.NewThreadLocal[string]()
+	tls3.Set("def")
+	Register("request_id", tls3)
+	assert.Equal(t, []slog.Attr{slog.String("request_id", "def"), slog.Int("tenant", 1)}, Attrs())
+	//
+	assert.Panics(t, func() {
+		Register("", tls)
+	})
+	assert.Panics(t, func() {
+		Register[string]("key", nil)
+	})
+}
+
+func TestUnregister(t *testing.T) {
+	tls := routine.NewThreadLocal[string]()
+	tls.Set("Hello")
+	Register("a", tls)
+	Register("b", tls)
+	Register("c", tls)
+	Unregister("b")
+	assert.Equal(t, []slog.Attr{slog.String("a", "Hello"), slog.String("c", "Hello")}, Attrs())
+	Unregister("a")
+	Unregister("c")
+	Unregister("c")
+	assert.Empty(t, Attrs())
+}
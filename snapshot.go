@@ -0,0 +1,42 @@
+package routine
+
+var emptySnapshot Snapshot = &snapshot{}
+
+type snapshot struct {
+	threadLocals            *threadLocalMap
+	inheritableThreadLocals *threadLocalMap
+}
+
+func (s *snapshot) Restore() func() {
+	return restoreMaps(copyMap(s.threadLocals, threadLocalIndexes, copyPlain), copyMap(s.inheritableThreadLocals, inheritableThreadLocalIndexes, copyClone))
+}
+
+func (s *snapshot) Run(fun Runnable) {
+	defer s.Restore()()
+	fun()
+}
+
+// restoreCurrentMaps installs a plain copy of the maps of the current goroutine and returns a function to restore the previous ones.
+// The InheritPolicy of the ThreadLocals is not applied, so that the values are kept as is.
+//
+//go:norace
+func restoreCurrentMaps() func() {
+	t := currentThread(false)
+	if t == nil {
+		return restoreMaps(nil, nil)
+	}
+	return restoreMaps(copyMap(t.threadLocals, threadLocalIndexes, copyPlain), copyMap(t.inheritableThreadLocals, inheritableThreadLocalIndexes, copyPlain))
+}
+
+//go:norace
+func captureSnapshot(all bool) *snapshot {
+	t := currentThread(false)
+	if t == nil {
+		return &snapshot{}
+	}
+	s := &snapshot{inheritableThreadLocals: copyMap(t.inheritableThreadLocals, inheritableThreadLocalIndexes, copyInherit)}
+	if all {
+		s.threadLocals = copyMap(t.threadLocals, threadLocalIndexes, copyPlain)
+	}
+	return s
+}
@@ -0,0 +1,106 @@
+package routine
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCaptureSnapshot(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s := captureSnapshot(true)
+		assert.Nil(t, s.threadLocals)
+		assert.Nil(t, s.inheritableThreadLocals)
+		//
+		undo := s.Restore()
+		if routinexEnabled {
+			assert.NotNil(t, currentThread(false))
+		} else {
+			assert.Nil(t, currentThread(false))
+		}
+		undo()
+	}()
+	wg.Wait()
+}
+
+func TestSnapshot_Restore_Cloneable(t *testing.T) {
+	tls := NewThreadLocal[*personCloneable]()
+	inheritableTls := NewInheritableThreadLocal[*personCloneable]()
+	value := &personCloneable{Id: 1, Name: "Hello"}
+	tls.Set(value)
+	inheritableTls.Set(value)
+	s := captureSnapshot(true)
+	assert.NotNil(t, s.threadLocals)
+	assert.NotNil(t, s.inheritableThreadLocals)
+	//
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer s.Restore()()
+		assert.Same(t, value, tls.Get())
+		assert.NotSame(t, value, inheritableTls.Get())
+		assert.Equal(t, *value, *inheritableTls.Get())
+	}()
+	wg.Wait()
+}
+
+func TestCaptureSnapshot_Closed(t *testing.T) {
+	tls := NewThreadLocal[string]()
+	inheritableTls := NewInheritableThreadLocal[string]()
+	tls.Set("Hello")
+	inheritableTls.Set("World")
+	tlsImpl := tls.(*threadLocal[string])
+	inheritableTlsImpl := inheritableTls.(*inheritableThreadLocal[string])
+	tls.Close()
+	inheritableTls.Close()
+	s := captureSnapshot(true)
+	assert.Same(t, unset, s.threadLocals.table[tlsImpl.index])
+	assert.Same(t, unset, s.inheritableThreadLocals.table[inheritableTlsImpl.index])
+	//
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer s.Restore()()
+		mp := currentThread(false).threadLocals
+		assert.Same(t, unset, mp.table[tlsImpl.index])
+		mp = currentThread(false).inheritableThreadLocals
+		assert.Same(t, unset, mp.table[inheritableTlsImpl.index])
+	}()
+	wg.Wait()
+}
+
+func TestSnapshot_Restore_CloneTwice(t *testing.T) {
+	tls := NewInheritableThreadLocal[*personCloneable]()
+	defer tls.Close()
+	tls.Set(&personCloneable{Id: 1, Name: "Hello"})
+	s := captureSnapshot(false)
+	s.Run(func() {
+		assert.Equal(t, "Hello", tls.Get().Name)
+		tls.Get().Name = "first"
+	})
+	s.Run(func() {
+		assert.Equal(t, "Hello", tls.Get().Name)
+	})
+	assert.Equal(t, "Hello", tls.Get().Name)
+	//
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.Run(func() {
+				tls.Get().Name = "concurrent"
+			})
+		}()
+	}
+	wg.Wait()
+	s.Run(func() {
+		assert.Equal(t, "Hello", tls.Get().Name)
+	})
+}
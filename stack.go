@@ -32,3 +32,42 @@ func isPanicRuntime(name string) bool {
 	const n = len(runtimePkgPrefix)
 	return len(name) > n && name[:n] == runtimePkgPrefix && strings.Contains(strings.ToLower(name[n:]), runtimePanic)
 }
+
+func newFrame(frame runtime.Frame, hidden bool) Frame {
+	return Frame{Function: frame.Function, File: frame.File, Line: frame.Line, Package: funcPackage(frame.Function), Hidden: hidden, offset: frame.PC - frame.Entry}
+}
+
+// funcPackage returns the package path of the package path-qualified function name.
+// The dots in the last element of the package path are escaped as "%2e" by the compiler, e.g. "gopkg.in/yaml%2ev3.Unmarshal".
+// The unescaped major version suffix is also recognized, e.g. "gopkg.in/yaml.v3.Unmarshal".
+func funcPackage(name string) string {
+	lastSlash := strings.LastIndexByte(name, '/')
+	if lastSlash < 0 {
+		lastSlash = 0
+	}
+	end := lastSlash
+	for {
+		dot := strings.IndexByte(name[end:], '.')
+		if dot < 0 {
+			return ""
+		}
+		end += dot
+		if lastSlash == 0 || !isVersionSuffix(name[end+1:]) {
+			break
+		}
+		end++
+	}
+	return strings.ReplaceAll(name[:end], "%2e", ".")
+}
+
+// isVersionSuffix reports whether the name starts with a major version element followed by a dot, e.g. "v3.Unmarshal".
+func isVersionSuffix(name string) bool {
+	if len(name) < 2 || name[0] != 'v' {
+		return false
+	}
+	i := 1
+	for i < len(name) && name[i] >= '0' && name[i] <= '9' {
+		i++
+	}
+	return i > 1 && i < len(name) && name[i] == '.'
+}
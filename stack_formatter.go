@@ -0,0 +1,170 @@
+package routine
+
+import (
+	"bytes"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+)
+
+type stackFormatterHolder struct {
+	formatter StackFormatter
+}
+
+var globalStackFormatter atomic.Value
+
+func loadStackFormatter() StackFormatter {
+	if holder, ok := globalStackFormatter.Load().(*stackFormatterHolder); ok {
+		return holder.formatter
+	}
+	return StackFormatterDotNet
+}
+
+func storeStackFormatter(formatter StackFormatter) {
+	globalStackFormatter.Store(&stackFormatterHolder{formatter: formatter})
+}
+
+type dotNetStackFormatter struct{}
+
+func (*dotNetStackFormatter) Format(re RuntimeError) string {
+	builder := &bytes.Buffer{}
+	runtimeErrorPrintStackTrace(re, builder)
+	runtimeErrorPrintCreatedBy(re, builder)
+	return builder.String()
+}
+
+type goStackFormatter struct{}
+
+func (*goStackFormatter) Format(re RuntimeError) string {
+	chain := runtimeErrorChain(re)
+	builder := &bytes.Buffer{}
+	// the innermost error panics first
+	for i := len(chain) - 1; i >= 0; i-- {
+		if i != len(chain)-1 {
+			builder.WriteString(newLine)
+			builder.WriteString("\t")
+		}
+		builder.WriteString("panic: ")
+		builder.WriteString(runtimeErrorTitle(chain[i]))
+		if i != 0 {
+			builder.WriteString(" [recovered]")
+		}
+	}
+	for _, err := range chain {
+		builder.WriteString(newLine)
+		builder.WriteString(newLine)
+		builder.WriteString("goroutine ")
+		builder.WriteString(strconv.FormatUint(err.Goid(), 10))
+		builder.WriteString(" [running]:")
+		for _, frame := range err.Frames() {
+			if !frame.Hidden {
+				builder.WriteString(newLine)
+				builder.WriteString(frame.Function)
+				builder.WriteString("(...)")
+				goStackFormatterPrintFile(frame, builder)
+			}
+		}
+		if frame, ok := runtimeErrorCreatedBy(err); ok {
+			builder.WriteString(newLine)
+			builder.WriteString(wordCreatedBy)
+			builder.WriteString(" ")
+			builder.WriteString(frame.Function)
+			goStackFormatterPrintFile(frame, builder)
+		}
+		for _, ancestor := range err.Ancestors() {
+			frame := ancestor.Frame()
+			builder.WriteString(newLine)
+			builder.WriteString("[originating from goroutine ")
+			builder.WriteString(strconv.FormatUint(ancestor.Goid, 10))
+			builder.WriteString("]:")
+			builder.WriteString(newLine)
+			builder.WriteString(frame.Function)
+			builder.WriteString("(...)")
+			goStackFormatterPrintFile(frame, builder)
+		}
+	}
+	return builder.String()
+}
+
+func goStackFormatterPrintFile(frame Frame, builder *bytes.Buffer) {
+	builder.WriteString(newLine)
+	builder.WriteString("\t")
+	builder.WriteString(frame.File)
+	builder.WriteString(":")
+	builder.WriteString(strconv.Itoa(frame.Line))
+	builder.WriteString(" +0x")
+	builder.WriteString(strconv.FormatUint(uint64(frame.offset), 16))
+}
+
+type javaStackFormatter struct{}
+
+func (*javaStackFormatter) Format(re RuntimeError) string {
+	builder := &bytes.Buffer{}
+	for i, err := range runtimeErrorChain(re) {
+		if i != 0 {
+			builder.WriteString(newLine)
+			builder.WriteString("Caused by: ")
+		}
+		builder.WriteString(runtimeErrorTitle(err))
+		for _, frame := range err.Frames() {
+			if !frame.Hidden {
+				builder.WriteString(newLine)
+				builder.WriteString("\t")
+				builder.WriteString(wordAt)
+				builder.WriteString(" ")
+				builder.WriteString(frame.Function)
+				builder.WriteString("(")
+				builder.WriteString(frame.File)
+				builder.WriteString(":")
+				builder.WriteString(strconv.Itoa(frame.Line))
+				builder.WriteString(")")
+			}
+		}
+	}
+	return builder.String()
+}
+
+type compactStackFormatter struct{}
+
+func (*compactStackFormatter) Format(re RuntimeError) string {
+	builder := &bytes.Buffer{}
+	for i, err := range runtimeErrorChain(re) {
+		if i != 0 {
+			builder.WriteString("; caused by ")
+		}
+		builder.WriteString(runtimeErrorTitle(err))
+		for _, frame := range err.Frames() {
+			if !frame.Hidden {
+				builder.WriteString(" ")
+				builder.WriteString(wordAt)
+				builder.WriteString(" ")
+				builder.WriteString(frame.Function)
+				builder.WriteString("(")
+				builder.WriteString(filepath.Base(frame.File))
+				builder.WriteString(":")
+				builder.WriteString(strconv.Itoa(frame.Line))
+				builder.WriteString(")")
+				break
+			}
+		}
+	}
+	return builder.String()
+}
+
+// runtimeErrorChain returns the error and its causes, the outermost error comes first.
+func runtimeErrorChain(re RuntimeError) []RuntimeError {
+	var chain []RuntimeError
+	for err := re; err != nil; err = err.Cause() {
+		chain = append(chain, err)
+	}
+	return chain
+}
+
+// runtimeErrorTitle returns the type name and the message of the error.
+func runtimeErrorTitle(re RuntimeError) string {
+	message := re.Message()
+	if len(message) == 0 {
+		return runtimeErrorTypeName(re)
+	}
+	return runtimeErrorTypeName(re) + ": " + message
+}
@@ -0,0 +1,82 @@
+package routine
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDotNetStackFormatter_Format(t *testing.T) {
+	err := NewRuntimeErrorWithMessageCause("outer", NewRuntimeError("inner"))
+	assert.Equal(t, runtimeErrorError(err), StackFormatterDotNet.Format(err))
+	lines := strings.Split(StackFormatterDotNet.Format(err), newLine)
+	assert.Equal(t, "RuntimeError: outer", lines[0])
+	assert.Equal(t, " ---> RuntimeError: inner", lines[1])
+}
+
+func TestGoStackFormatter_Format(t *testing.T) {
+	err := NewRuntimeErrorWithMessageCause("outer", NewRuntimeError(nil))
+	lines := strings.Split(StackFormatterGo.Format(err), newLine)
+	assert.Equal(t, 18, len(lines))
+	assert.Equal(t, "panic: RuntimeError [recovered]", lines[0])
+	assert.Equal(t, "\tpanic: RuntimeError: outer", lines[1])
+	assert.Equal(t, "", lines[2])
+	assert.Equal(t, "goroutine "+strconv.FormatUint(err.Goid(), 10)+" [running]:", lines[3])
+	assert.Equal(t, "github.com/timandy/routine.TestGoStackFormatter_Format(...)", lines[4])
+	assert.True(t, strings.HasPrefix(lines[5], "\t"))
+	assert.Regexp(t, `stack_formatter_test\.go:20 \+0x[0-9a-f]+$`, lines[5])
+	assert.Equal(t, "testing.tRunner(...)", lines[6])
+	assert.True(t, strings.HasPrefix(lines[8], "created by testing.(*T).Run"))
+	assert.Regexp(t, `\t.+:\d+ \+0x[0-9a-f]+$`, lines[9])
+	assert.Equal(t, "", lines[10])
+	assert.Equal(t, "goroutine "+strconv.FormatUint(err.Goid(), 10)+" [running]:", lines[11])
+	assert.Equal(t, "github.com/timandy/routine.TestGoStackFormatter_Format(...)", lines[12])
+	assert.Regexp(t, `stack_formatter_test\.go:20 \+0x[0-9a-f]+$`, lines[13])
+}
+
+func TestJavaStackFormatter_Format(t *testing.T) {
+	err := NewRuntimeErrorWithMessageCause("outer", NewRuntimeError("inner"))
+	lines := strings.Split(StackFormatterJava.Format(err), newLine)
+	assert.Equal(t, 6, len(lines))
+	assert.Equal(t, "RuntimeError: outer", lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], "\tat github.com/timandy/routine.TestJavaStackFormatter_Format("))
+	assert.True(t, strings.HasSuffix(lines[1], "stack_formatter_test.go:40)"))
+	assert.True(t, strings.HasPrefix(lines[2], "\tat testing.tRunner("))
+	assert.Equal(t, "Caused by: RuntimeError: inner", lines[3])
+	assert.True(t, strings.HasSuffix(lines[4], "stack_formatter_test.go:40)"))
+}
+
+func TestCompactStackFormatter_Format(t *testing.T) {
+	err := NewRuntimeErrorWithMessageCause("outer", NewRuntimeError("inner"))
+	assert.Equal(t, "RuntimeError: outer at github.com/timandy/routine.TestCompactStackFormatter_Format(stack_formatter_test.go:52); caused by RuntimeError: inner at github.com/timandy/routine.TestCompactStackFormatter_Format(stack_formatter_test.go:52)", StackFormatterCompact.Format(err))
+	//
+	err2 := &runtimeError{message: "Hello"}
+	assert.Equal(t, "RuntimeError: Hello", StackFormatterCompact.Format(err2))
+}
+
+func TestRuntimeErrorChain(t *testing.T) {
+	inner := NewRuntimeError("inner")
+	err := NewRuntimeErrorWithMessageCause("outer", inner)
+	chain := runtimeErrorChain(err)
+	assert.Equal(t, 2, len(chain))
+	assert.Same(t, err, chain[0])
+	assert.Same(t, inner, chain[1])
+}
+
+func TestRuntimeErrorTitle(t *testing.T) {
+	assert.Equal(t, "RuntimeError", runtimeErrorTitle(NewRuntimeError(nil)))
+	assert.Equal(t, "RuntimeError: Hello", runtimeErrorTitle(NewRuntimeError("Hello")))
+}
+
+func TestGoStackFormatter_Format_Ancestors(t *testing.T) {
+	goid := Goid()
+	task := GoWaitResult(func(token CancelToken) RuntimeError {
+		return NewRuntimeError(nil)
+	})
+	lines := strings.Split(StackFormatterGo.Format(task.Get()), newLine)
+	assert.Equal(t, "[originating from goroutine "+strconv.FormatUint(goid, 10)+"]:", lines[len(lines)-3])
+	assert.Equal(t, "github.com/timandy/routine.TestGoStackFormatter_Format_Ancestors(...)", lines[len(lines)-2])
+	assert.Regexp(t, `stack_formatter_test\.go:75 \+0x[0-9a-f]+$`, lines[len(lines)-1])
+}
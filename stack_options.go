@@ -0,0 +1,72 @@
+package routine
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+const defaultStackDepth = 100
+
+var (
+	defaultStackOptions = &StackOptions{}
+	globalStackOptions  atomic.Value
+)
+
+func loadStackOptions() *StackOptions {
+	if options, ok := globalStackOptions.Load().(*StackOptions); ok {
+		return options
+	}
+	return defaultStackOptions
+}
+
+func storeStackOptions(options *StackOptions) {
+	globalStackOptions.Store(options)
+}
+
+// clone returns a copy of the options, the packages are copied so that the caller can not modify them after stored.
+func (options *StackOptions) clone() *StackOptions {
+	clone := *options
+	clone.AllowPackages = cloneStrings(options.AllowPackages)
+	clone.DenyPackages = cloneStrings(options.DenyPackages)
+	return &clone
+}
+
+func (options *StackOptions) depth() int {
+	if options.MaxDepth <= 0 {
+		return defaultStackDepth
+	}
+	return options.MaxDepth
+}
+
+func (options *StackOptions) skip() int {
+	if options.Skip < 0 {
+		return 0
+	}
+	return options.Skip
+}
+
+// allow returns false if the pkg is filtered by the AllowPackages or DenyPackages.
+func (options *StackOptions) allow(pkg string) bool {
+	if len(options.AllowPackages) > 0 && !matchPackages(pkg, options.AllowPackages) {
+		return false
+	}
+	return !matchPackages(pkg, options.DenyPackages)
+}
+
+func matchPackages(pkg string, packages []string) bool {
+	for _, prefix := range packages {
+		if pkg == prefix || strings.HasPrefix(pkg, prefix) && pkg[len(prefix)] == '/' {
+			return true
+		}
+	}
+	return false
+}
+
+func cloneStrings(a []string) []string {
+	if a == nil {
+		return nil
+	}
+	clone := make([]string, len(a))
+	copy(clone, a)
+	return clone
+}
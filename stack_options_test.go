@@ -0,0 +1,61 @@
+package routine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadStackOptions(t *testing.T) {
+	assert.Equal(t, *defaultStackOptions, *loadStackOptions())
+	defer storeStackOptions(defaultStackOptions)
+	options := &StackOptions{MaxDepth: 1}
+	storeStackOptions(options)
+	assert.Same(t, options, loadStackOptions())
+}
+
+func TestStackOptions_Clone(t *testing.T) {
+	options := &StackOptions{MaxDepth: 1, AllowPackages: []string{"main"}}
+	clone := options.clone()
+	assert.NotSame(t, options, clone)
+	assert.Equal(t, *options, *clone)
+	assert.Nil(t, clone.DenyPackages)
+	clone.AllowPackages[0] = "runtime"
+	assert.Equal(t, "main", options.AllowPackages[0])
+}
+
+func TestStackOptions_Depth(t *testing.T) {
+	assert.Equal(t, defaultStackDepth, (&StackOptions{}).depth())
+	assert.Equal(t, defaultStackDepth, (&StackOptions{MaxDepth: -1}).depth())
+	assert.Equal(t, 10, (&StackOptions{MaxDepth: 10}).depth())
+}
+
+func TestStackOptions_Skip(t *testing.T) {
+	assert.Equal(t, 0, (&StackOptions{}).skip())
+	assert.Equal(t, 0, (&StackOptions{Skip: -1}).skip())
+	assert.Equal(t, 2, (&StackOptions{Skip: 2}).skip())
+}
+
+func TestStackOptions_Allow(t *testing.T) {
+	options := &StackOptions{}
+	assert.True(t, options.allow("testing"))
+	//
+	options2 := &StackOptions{DenyPackages: []string{"github.com/stretchr/testify"}}
+	assert.True(t, options2.allow("testing"))
+	assert.False(t, options2.allow("github.com/stretchr/testify"))
+	assert.False(t, options2.allow("github.com/stretchr/testify/assert"))
+	assert.True(t, options2.allow("github.com/stretchr/testifyx"))
+	//
+	options3 := &StackOptions{AllowPackages: []string{"github.com/timandy"}, DenyPackages: []string{"github.com/timandy/routine/g"}}
+	assert.False(t, options3.allow("testing"))
+	assert.True(t, options3.allow("github.com/timandy/routine"))
+	assert.False(t, options3.allow("github.com/timandy/routine/g"))
+}
+
+func TestMatchPackages(t *testing.T) {
+	assert.False(t, matchPackages("testing", nil))
+	assert.True(t, matchPackages("testing", []string{"testing"}))
+	assert.True(t, matchPackages("testing/quick", []string{"runtime", "testing"}))
+	assert.False(t, matchPackages("test", []string{"testing"}))
+	assert.False(t, matchPackages("testingx", []string{"testing"}))
+}
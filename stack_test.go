@@ -92,3 +92,18 @@ func captureStackDeepRecursive(cur int, deep int) []uintptr {
 	}
 	return captureStackTrace(0, 100)
 }
+
+func TestFuncPackage(t *testing.T) {
+	assert.Equal(t, "", funcPackage(""))
+	assert.Equal(t, "", funcPackage("make"))
+	assert.Equal(t, "main", funcPackage("main.main"))
+	assert.Equal(t, "runtime", funcPackage("runtime.goexit"))
+	assert.Equal(t, "github.com/timandy/routine", funcPackage("github.com/timandy/routine.(*futureTask[...]).Run"))
+	assert.Equal(t, "github.com/timandy/routine", funcPackage("github.com/timandy/routine.Go.func1"))
+	assert.Equal(t, "gopkg.in/yaml.v3", funcPackage("gopkg.in/yaml%2ev3.Unmarshal"))
+	assert.Equal(t, "gopkg.in/yaml.v3", funcPackage("gopkg.in/yaml%2ev3.(*decoder).unmarshal"))
+	assert.Equal(t, "gopkg.in/yaml.v3", funcPackage("gopkg.in/yaml.v3.Decode"))
+	assert.Equal(t, "gopkg.in/yaml.v3", funcPackage("gopkg.in/yaml.v3.(*Decoder).Decode"))
+	assert.Equal(t, "example.com/pkg", funcPackage("example.com/pkg.v1"))
+	assert.Equal(t, "", funcPackage("example.com/pkg"))
+}
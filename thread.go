@@ -90,3 +90,19 @@ func extractThread(gp *g, label unsafe.Pointer) (t *thread, magic uint64, id uin
 	t = (*thread)(label)
 	return t, t.magic, t.id
 }
+
+// storeLabels replaces the pprof labels of the current goroutine and keeps the threadLocals and inheritableThreadLocals.
+// The thread is copied rather than modified, because the profiler may read the labels of the samples later.
+//
+//go:norace
+func storeLabels(mp labelMap) {
+	gp := getg()
+	t := currentThread(false)
+	if t == nil {
+		gp.setLabels(newLabelPointer(mp))
+		return
+	}
+	newt := &thread{labels: mp, magic: threadMagic, id: t.id, threadLocals: t.threadLocals, inheritableThreadLocals: t.inheritableThreadLocals}
+	runtime.SetFinalizer(newt, (*thread).finalize)
+	gp.setLabels(unsafe.Pointer(newt))
+}
@@ -17,3 +17,10 @@ func currentThread(create bool) *thread {
 	gp := getg()
 	return (*thread)(add(unsafe.Pointer(gp), offsetThreadLocals))
 }
+
+// storeLabels replaces the pprof labels of the current goroutine, the thread is not stored in the labels in this mode.
+//
+//go:norace
+func storeLabels(mp labelMap) {
+	getg().setLabels(newLabelPointer(mp))
+}
@@ -2,7 +2,10 @@ package routine
 
 import "sync/atomic"
 
-var threadLocalIndex int32 = -1
+var (
+	threadLocalIndex   int32 = -1
+	threadLocalIndexes       = &threadLocalIndexPool{}
+)
 
 func nextThreadLocalIndex() int {
 	index := atomic.AddInt32(&threadLocalIndex, 1)
@@ -13,31 +16,32 @@ func nextThreadLocalIndex() int {
 	return int(index)
 }
 
+func acquireThreadLocalIndex() (index int, version uint32) {
+	if index, version, ok := threadLocalIndexes.poll(); ok {
+		return index, version
+	}
+	return nextThreadLocalIndex(), 0
+}
+
 type threadLocal[T any] struct {
+	name     string
 	index    int
+	version  uint32
+	closed   int32
 	supplier Supplier[T]
 }
 
 func (tls *threadLocal[T]) Get() T {
 	t := currentThread(true)
-	mp := tls.getMap(t)
-	if mp != nil {
-		v := mp.get(tls.index)
-		if v != unset {
-			return entryValue[T](v)
-		}
+	if value, ok := tls.getValue(t); ok {
+		return value
 	}
 	return tls.setInitialValue(t)
 }
 
 func (tls *threadLocal[T]) Set(value T) {
 	t := currentThread(true)
-	mp := tls.getMap(t)
-	if mp != nil {
-		mp.set(tls.index, entry(value))
-	} else {
-		tls.createMap(t, value)
-	}
+	tls.setValue(t, value)
 }
 
 func (tls *threadLocal[T]) Remove() {
@@ -47,7 +51,66 @@ func (tls *threadLocal[T]) Remove() {
 	}
 	mp := tls.getMap(t)
 	if mp != nil {
-		mp.remove(tls.index)
+		mp.remove(tls.index, tls.version)
+	}
+	if hasLabelMirrors() {
+		mirrorThreadLocal(false, tls.index, tls.version, unset)
+	}
+}
+
+func (tls *threadLocal[T]) IsSet() bool {
+	t := currentThread(false)
+	if t == nil {
+		return false
+	}
+	_, ok := tls.getValue(t)
+	return ok
+}
+
+func (tls *threadLocal[T]) GetOrSet(supplier Supplier[T]) T {
+	t := currentThread(true)
+	if value, ok := tls.getValue(t); ok {
+		return value
+	}
+	value := supplier()
+	// the thread may be replaced by the supplier, e.g. the pprof labels changed
+	tls.setValue(currentThread(true), value)
+	return value
+}
+
+func (tls *threadLocal[T]) Update(fun func(old T) T) T {
+	t := currentThread(true)
+	old, ok := tls.getValue(t)
+	if !ok {
+		old = tls.initialValue()
+	}
+	value := fun(old)
+	// the thread may be replaced by the supplier or the fun, e.g. the pprof labels changed
+	tls.setValue(currentThread(true), value)
+	return value
+}
+
+func (tls *threadLocal[T]) Swap(value T) (old T) {
+	t := currentThread(true)
+	old, ok := tls.getValue(t)
+	if !ok {
+		old = tls.initialValue()
+		// the thread may be replaced by the supplier, e.g. the pprof labels changed
+		t = currentThread(true)
+	}
+	tls.setValue(t, value)
+	return old
+}
+
+func (tls *threadLocal[T]) Name() string {
+	return tls.name
+}
+
+func (tls *threadLocal[T]) Close() {
+	if atomic.CompareAndSwapInt32(&tls.closed, 0, 1) {
+		unregisterThreadLocal(tls.name)
+		removeThreadLocalLabelMirror(false, tls.index, tls.version)
+		threadLocalIndexes.offer(tls.index, tls.version)
 	}
 }
 
@@ -59,18 +122,45 @@ func (tls *threadLocal[T]) getMap(t *thread) *threadLocalMap {
 //go:norace
 func (tls *threadLocal[T]) createMap(t *thread, firstValue T) {
 	mp := &threadLocalMap{}
-	mp.set(tls.index, entry(firstValue))
+	mp.set(tls.index, tls.version, entry(firstValue))
 	t.threadLocals = mp
 }
 
-func (tls *threadLocal[T]) setInitialValue(t *thread) T {
-	value := tls.initialValue()
+// getValue returns the value of the current goroutine and whether the value was set before.
+func (tls *threadLocal[T]) getValue(t *thread) (T, bool) {
 	mp := tls.getMap(t)
 	if mp != nil {
-		mp.set(tls.index, entry(value))
+		v := mp.get(tls.index, tls.version)
+		if v != unset {
+			return entryValue[T](v), true
+		}
+	}
+	var defaultValue T
+	return defaultValue, false
+}
+
+// setValue stores the value, the map must be resolved again because it may be created during the supplier or updater called.
+// The t must be the current thread, it must be resolved again after any user function called.
+func (tls *threadLocal[T]) setValue(t *thread, value T) {
+	mp := tls.getMap(t)
+	if mp != nil {
+		mp.set(tls.index, tls.version, entry(value))
 	} else {
 		tls.createMap(t, value)
 	}
+	trackThreadLocal(t, tls.index, tls.version, tls.name)
+	if hasLabelMirrors() {
+		mirrorThreadLocal(false, tls.index, tls.version, entry(value))
+	}
+}
+
+func (tls *threadLocal[T]) setInitialValue(t *thread) T {
+	value := tls.initialValue()
+	if tls.supplier != nil {
+		// the thread may be replaced by the supplier, e.g. the pprof labels changed
+		t = currentThread(true)
+	}
+	tls.setValue(t, value)
 	return value
 }
 
@@ -0,0 +1,64 @@
+package routine
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+type threadLocalSlot struct {
+	index   int
+	version uint32
+}
+
+// threadLocalIndexPool holds the indexes released by closed ThreadLocals.
+type threadLocalIndexPool struct {
+	mutex    sync.Mutex
+	slots    []threadLocalSlot // sorted by index in descending order
+	versions atomic.Value      // []uint32, the lowest version which may still be owned of each index
+}
+
+// poll takes the lowest released index, so that the tables of goroutines stay as short as possible.
+func (pool *threadLocalIndexPool) poll() (index int, version uint32, ok bool) {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+	n := len(pool.slots)
+	if n == 0 {
+		return 0, 0, false
+	}
+	slot := pool.slots[n-1]
+	pool.slots = pool.slots[:n-1]
+	return slot.index, slot.version, true
+}
+
+// offer releases the index, the next owner of the index will get a newer version.
+func (pool *threadLocalIndexPool) offer(index int, version uint32) {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+	i := sort.Search(len(pool.slots), func(i int) bool {
+		return pool.slots[i].index < index
+	})
+	pool.slots = append(pool.slots, threadLocalSlot{})
+	copy(pool.slots[i+1:], pool.slots[i:])
+	pool.slots[i] = threadLocalSlot{index: index, version: version + 1}
+	pool.storeVersion(index, version+1)
+}
+
+// isStale reports whether the value of the index and version was stored by a closed owner.
+func (pool *threadLocalIndexPool) isStale(index int, version uint32) bool {
+	versions, _ := pool.versions.Load().([]uint32)
+	return index < len(versions) && version < versions[index]
+}
+
+// storeVersion copies the versions on write, so that the readers need not to lock, it must be called with the mutex held.
+func (pool *threadLocalIndexPool) storeVersion(index int, version uint32) {
+	oldVersions, _ := pool.versions.Load().([]uint32)
+	length := len(oldVersions)
+	if index >= length {
+		length = index + 1
+	}
+	versions := make([]uint32, length)
+	copy(versions, oldVersions)
+	versions[index] = version
+	pool.versions.Store(versions)
+}
@@ -0,0 +1,48 @@
+package routine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThreadLocalIndexPool(t *testing.T) {
+	pool := &threadLocalIndexPool{}
+	_, _, ok := pool.poll()
+	assert.False(t, ok)
+	//
+	pool.offer(5, 0)
+	pool.offer(1, 3)
+	pool.offer(3, 1)
+	index, version, ok := pool.poll()
+	assert.True(t, ok)
+	assert.Equal(t, 1, index)
+	assert.Equal(t, uint32(4), version)
+	index, version, ok = pool.poll()
+	assert.True(t, ok)
+	assert.Equal(t, 3, index)
+	assert.Equal(t, uint32(2), version)
+	index, version, ok = pool.poll()
+	assert.True(t, ok)
+	assert.Equal(t, 5, index)
+	assert.Equal(t, uint32(1), version)
+	_, _, ok = pool.poll()
+	assert.False(t, ok)
+}
+
+func TestThreadLocalIndexPool_IsStale(t *testing.T) {
+	pool := &threadLocalIndexPool{}
+	assert.False(t, pool.isStale(3, 0))
+	//
+	pool.offer(3, 0)
+	assert.True(t, pool.isStale(3, 0))
+	assert.False(t, pool.isStale(3, 1))
+	assert.False(t, pool.isStale(2, 0))
+	assert.False(t, pool.isStale(5, 0))
+	//
+	index, version, ok := pool.poll()
+	assert.True(t, ok)
+	pool.offer(index, version)
+	assert.True(t, pool.isStale(3, 1))
+	assert.False(t, pool.isStale(3, 2))
+}
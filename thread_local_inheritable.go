@@ -2,7 +2,10 @@ package routine
 
 import "sync/atomic"
 
-var inheritableThreadLocalIndex int32 = -1
+var (
+	inheritableThreadLocalIndex   int32 = -1
+	inheritableThreadLocalIndexes       = &threadLocalIndexPool{}
+)
 
 func nextInheritableThreadLocalIndex() int {
 	index := atomic.AddInt32(&inheritableThreadLocalIndex, 1)
@@ -13,31 +16,32 @@ func nextInheritableThreadLocalIndex() int {
 	return int(index)
 }
 
+func acquireInheritableThreadLocalIndex() (index int, version uint32) {
+	if index, version, ok := inheritableThreadLocalIndexes.poll(); ok {
+		return index, version
+	}
+	return nextInheritableThreadLocalIndex(), 0
+}
+
 type inheritableThreadLocal[T any] struct {
+	name     string
 	index    int
+	version  uint32
+	closed   int32
 	supplier Supplier[T]
 }
 
 func (tls *inheritableThreadLocal[T]) Get() T {
 	t := currentThread(true)
-	mp := tls.getMap(t)
-	if mp != nil {
-		v := mp.get(tls.index)
-		if v != unset {
-			return entryValue[T](v)
-		}
+	if value, ok := tls.getValue(t); ok {
+		return value
 	}
 	return tls.setInitialValue(t)
 }
 
 func (tls *inheritableThreadLocal[T]) Set(value T) {
 	t := currentThread(true)
-	mp := tls.getMap(t)
-	if mp != nil {
-		mp.set(tls.index, entry(value))
-	} else {
-		tls.createMap(t, value)
-	}
+	tls.setValue(t, value)
 }
 
 func (tls *inheritableThreadLocal[T]) Remove() {
@@ -47,7 +51,67 @@ func (tls *inheritableThreadLocal[T]) Remove() {
 	}
 	mp := tls.getMap(t)
 	if mp != nil {
-		mp.remove(tls.index)
+		mp.remove(tls.index, tls.version)
+	}
+	if hasLabelMirrors() {
+		mirrorThreadLocal(true, tls.index, tls.version, unset)
+	}
+}
+
+func (tls *inheritableThreadLocal[T]) IsSet() bool {
+	t := currentThread(false)
+	if t == nil {
+		return false
+	}
+	_, ok := tls.getValue(t)
+	return ok
+}
+
+func (tls *inheritableThreadLocal[T]) GetOrSet(supplier Supplier[T]) T {
+	t := currentThread(true)
+	if value, ok := tls.getValue(t); ok {
+		return value
+	}
+	value := supplier()
+	// the thread may be replaced by the supplier, e.g. the pprof labels changed
+	tls.setValue(currentThread(true), value)
+	return value
+}
+
+func (tls *inheritableThreadLocal[T]) Update(fun func(old T) T) T {
+	t := currentThread(true)
+	old, ok := tls.getValue(t)
+	if !ok {
+		old = tls.initialValue()
+	}
+	value := fun(old)
+	// the thread may be replaced by the supplier or the fun, e.g. the pprof labels changed
+	tls.setValue(currentThread(true), value)
+	return value
+}
+
+func (tls *inheritableThreadLocal[T]) Swap(value T) (old T) {
+	t := currentThread(true)
+	old, ok := tls.getValue(t)
+	if !ok {
+		old = tls.initialValue()
+		// the thread may be replaced by the supplier, e.g. the pprof labels changed
+		t = currentThread(true)
+	}
+	tls.setValue(t, value)
+	return old
+}
+
+func (tls *inheritableThreadLocal[T]) Name() string {
+	return tls.name
+}
+
+func (tls *inheritableThreadLocal[T]) Close() {
+	if atomic.CompareAndSwapInt32(&tls.closed, 0, 1) {
+		unregisterThreadLocal(tls.name)
+		removeThreadLocalLabelMirror(true, tls.index, tls.version)
+		removeInheritFunc(tls.index, tls.version)
+		inheritableThreadLocalIndexes.offer(tls.index, tls.version)
 	}
 }
 
@@ -59,18 +123,44 @@ func (tls *inheritableThreadLocal[T]) getMap(t *thread) *threadLocalMap {
 //go:norace
 func (tls *inheritableThreadLocal[T]) createMap(t *thread, firstValue T) {
 	mp := &threadLocalMap{}
-	mp.set(tls.index, entry(firstValue))
+	mp.set(tls.index, tls.version, entry(firstValue))
 	t.inheritableThreadLocals = mp
 }
 
-func (tls *inheritableThreadLocal[T]) setInitialValue(t *thread) T {
-	value := tls.initialValue()
+// getValue returns the value of the current goroutine and whether the value was set before.
+func (tls *inheritableThreadLocal[T]) getValue(t *thread) (T, bool) {
 	mp := tls.getMap(t)
 	if mp != nil {
-		mp.set(tls.index, entry(value))
+		v := mp.get(tls.index, tls.version)
+		if v != unset {
+			return entryValue[T](v), true
+		}
+	}
+	var defaultValue T
+	return defaultValue, false
+}
+
+// setValue stores the value, the map must be resolved again because it may be created during the supplier or updater called.
+// The t must be the current thread, it must be resolved again after any user function called.
+func (tls *inheritableThreadLocal[T]) setValue(t *thread, value T) {
+	mp := tls.getMap(t)
+	if mp != nil {
+		mp.set(tls.index, tls.version, entry(value))
 	} else {
 		tls.createMap(t, value)
 	}
+	if hasLabelMirrors() {
+		mirrorThreadLocal(true, tls.index, tls.version, entry(value))
+	}
+}
+
+func (tls *inheritableThreadLocal[T]) setInitialValue(t *thread) T {
+	value := tls.initialValue()
+	if tls.supplier != nil {
+		// the thread may be replaced by the supplier, e.g. the pprof labels changed
+		t = currentThread(true)
+	}
+	tls.setValue(t, value)
 	return value
 }
 
@@ -296,3 +296,122 @@ func TestInheritableThreadLocal_Cloneable(t *testing.T) {
 	assert.Equal(t, 2, p6.Id)
 	assert.Equal(t, "Andy", p6.Name)
 }
+
+func TestInheritableThreadLocal_Close(t *testing.T) {
+	tls := NewInheritableThreadLocal[string]()
+	tls.Set("Hello")
+	index := tls.(*inheritableThreadLocal[string]).index
+	version := tls.(*inheritableThreadLocal[string]).version
+	tls.Close()
+	tls.Close()
+	//
+	tls2 := NewInheritableThreadLocalWithInitial[string](func() string {
+		return "World"
+	})
+	assert.Equal(t, index, tls2.(*inheritableThreadLocal[string]).index)
+	assert.Equal(t, version+1, tls2.(*inheritableThreadLocal[string]).version)
+	assert.Equal(t, "World", tls2.Get())
+	//closed thread local can not affect the new owner
+	tls.Set("Hello")
+	tls.Remove()
+	assert.Equal(t, "World", tls2.Get())
+	assert.Equal(t, "", tls.Get())
+	//
+	tls3 := NewInheritableThreadLocal[string]()
+	assert.NotEqual(t, index, tls3.(*inheritableThreadLocal[string]).index)
+	tls2.Close()
+	tls3.Close()
+}
+
+func TestInheritableThreadLocal_IsSet(t *testing.T) {
+	tls := NewInheritableThreadLocalWithInitial[int](func() int {
+		return 1
+	})
+	assert.False(t, tls.IsSet())
+	assert.Equal(t, 1, tls.Get())
+	assert.True(t, tls.IsSet())
+	tls.Remove()
+	assert.False(t, tls.IsSet())
+	tls.Set(0)
+	assert.True(t, tls.IsSet())
+	//
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		assert.False(t, tls.IsSet())
+		if !routinexEnabled {
+			assert.Nil(t, currentThread(false))
+		}
+	}()
+	wg.Wait()
+	tls.Close()
+}
+
+func TestInheritableThreadLocal_GetOrSet(t *testing.T) {
+	tls := NewInheritableThreadLocalWithInitial[int](func() int {
+		return 1
+	})
+	assert.Equal(t, 2, tls.GetOrSet(func() int {
+		return 2
+	}))
+	assert.Equal(t, 2, tls.GetOrSet(func() int {
+		return 3
+	}))
+	assert.Equal(t, 2, tls.Get())
+	//
+	tls2 := NewInheritableThreadLocal[int]()
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		assert.Equal(t, 4, tls.GetOrSet(func() int {
+			tls2.Set(5)
+			return 4
+		}))
+		assert.Equal(t, 4, tls.Get())
+		assert.Equal(t, 5, tls2.Get())
+	}()
+	wg.Wait()
+	tls.Close()
+	tls2.Close()
+}
+
+func TestInheritableThreadLocal_Update(t *testing.T) {
+	tls := NewInheritableThreadLocalWithInitial[int](func() int {
+		return 1
+	})
+	assert.Equal(t, 2, tls.Update(func(old int) int {
+		assert.Equal(t, 1, old)
+		return old + 1
+	}))
+	assert.Equal(t, 3, tls.Update(func(old int) int {
+		assert.Equal(t, 2, old)
+		return old + 1
+	}))
+	assert.Equal(t, 3, tls.Get())
+	//
+	tls2 := NewInheritableThreadLocal[[]string]()
+	tls2.Update(func(old []string) []string {
+		return append(old, "Hello")
+	})
+	tls2.Update(func(old []string) []string {
+		return append(old, "World")
+	})
+	assert.Equal(t, []string{"Hello", "World"}, tls2.Get())
+	tls.Close()
+	tls2.Close()
+}
+
+func TestInheritableThreadLocal_Swap(t *testing.T) {
+	tls := NewInheritableThreadLocalWithInitial[int](func() int {
+		return 1
+	})
+	assert.Equal(t, 1, tls.Swap(2))
+	assert.Equal(t, 2, tls.Swap(3))
+	assert.Equal(t, 3, tls.Get())
+	tls.Remove()
+	assert.Equal(t, 1, tls.Swap(4))
+	assert.Equal(t, 4, tls.Get())
+	tls.Close()
+}
@@ -9,33 +9,68 @@ type object struct {
 }
 
 type threadLocalMap struct {
-	table []entry
+	table    []entry
+	versions []uint32
 }
 
-func (mp *threadLocalMap) get(index int) entry {
+func (mp *threadLocalMap) get(index int, version uint32) entry {
 	lookup := mp.table
 	if index < len(lookup) {
-		return lookup[index]
+		current := mp.version(index)
+		if current == version {
+			return lookup[index]
+		}
+		if current < version {
+			// stale value of the previous owner
+			lookup[index] = unset
+			mp.setVersion(index, version)
+		}
 	}
 	return unset
 }
 
-func (mp *threadLocalMap) set(index int, value entry) {
+func (mp *threadLocalMap) set(index int, version uint32, value entry) {
 	lookup := mp.table
 	if index < len(lookup) {
-		lookup[index] = value
+		if mp.version(index) <= version {
+			lookup[index] = value
+			mp.setVersion(index, version)
+		}
 		return
 	}
 	mp.expandAndSet(index, value)
+	mp.setVersion(index, version)
 }
 
-func (mp *threadLocalMap) remove(index int) {
+func (mp *threadLocalMap) remove(index int, version uint32) {
 	lookup := mp.table
-	if index < len(lookup) {
+	if index < len(lookup) && mp.version(index) <= version {
 		lookup[index] = unset
 	}
 }
 
+func (mp *threadLocalMap) version(index int) uint32 {
+	if index < len(mp.versions) {
+		return mp.versions[index]
+	}
+	return 0
+}
+
+// setVersion stores the version of the slot, the versions are allocated when the first recycled index is used.
+func (mp *threadLocalMap) setVersion(index int, version uint32) {
+	if index < len(mp.versions) {
+		mp.versions[index] = version
+		return
+	}
+	if version == 0 {
+		return
+	}
+	versions := make([]uint32, len(mp.table))
+	copy(versions, mp.versions)
+	versions[index] = version
+	mp.versions = versions
+}
+
 func (mp *threadLocalMap) expandAndSet(index int, value entry) {
 	oldArray := mp.table
 	oldCapacity := len(oldArray)
@@ -61,38 +96,76 @@ func createInheritedMap() *threadLocalMap {
 	if parent == nil {
 		return nil
 	}
-	parentMap := parent.inheritableThreadLocals
-	if parentMap == nil {
+	return copyMap(parent.inheritableThreadLocals, inheritableThreadLocalIndexes, copyInherit)
+}
+
+type copyMode int
+
+const (
+	copyPlain   copyMode = iota // copy the values as is
+	copyInherit                 // inherit the values by the InheritPolicy of the ThreadLocals
+	copyClone                   // clone the Cloneable values of the ThreadLocals with InheritPolicyClone, e.g. restore a Snapshot
+)
+
+// copyMap returns a copy of the map without the values of closed ThreadLocals, the values are copied by the mode.
+func copyMap(mp *threadLocalMap, indexes *threadLocalIndexPool, mode copyMode) *threadLocalMap {
+	if mp == nil {
 		return nil
 	}
-	lookup := parentMap.table
+	lookup := mp.table
 	if lookup == nil {
 		return nil
 	}
 	table := make([]entry, len(lookup))
 	copy(table, lookup)
 	for i := 0; i < len(table); i++ {
-		if c, ok := entryAssert[Cloneable](table[i]); ok && !isNil(c) {
-			table[i] = entry(c.Clone())
+		if table[i] != unset && indexes.isStale(i, mp.version(i)) {
+			table[i] = unset
+		}
+	}
+	if mode != copyPlain {
+		handlers := loadInheritHandlers()
+		for i := 0; i < len(table); i++ {
+			if mode == copyInherit {
+				table[i] = inheritEntry(handlers, i, mp.version(i), table[i])
+			} else {
+				table[i] = cloneEntry(handlers, i, mp.version(i), table[i])
+			}
 		}
 	}
-	return &threadLocalMap{table: table}
+	var versions []uint32
+	if mp.versions != nil {
+		versions = make([]uint32, len(mp.versions))
+		copy(versions, mp.versions)
+	}
+	return &threadLocalMap{table: table, versions: versions}
 }
 
 //go:norace
 //go:linkname restoreInheritedMap routine.restoreInheritedMap
 func restoreInheritedMap(mp *threadLocalMap) func() {
-	t := currentThread(mp != nil)
+	return restoreMaps(nil, mp)
+}
+
+// restoreMaps replaces the maps of the current goroutine and returns a function to restore the previous ones.
+// The mirrored pprof labels are synchronized with the new maps.
+//
+//go:norace
+func restoreMaps(threadLocals, inheritableThreadLocals *threadLocalMap) func() {
+	t := currentThread(threadLocals != nil || inheritableThreadLocals != nil)
 	if t == nil {
-		// mp and t are nil
+		// maps and t are nil
 		return clearThread
 	}
 	threadLocalsBackup := t.threadLocals
 	inheritableThreadLocalsBackup := t.inheritableThreadLocals
-	t.threadLocals = nil
-	t.inheritableThreadLocals = mp
+	t.threadLocals = threadLocals
+	t.inheritableThreadLocals = inheritableThreadLocals
+	if hasLabelMirrors() {
+		syncLabelMirrors(t)
+	}
 	return func() {
-		resetThread(t, threadLocalsBackup, inheritableThreadLocalsBackup)
+		resetThread(threadLocalsBackup, inheritableThreadLocalsBackup)
 	}
 }
 
@@ -102,13 +175,25 @@ func clearThread() {
 	if t != nil {
 		t.threadLocals = nil
 		t.inheritableThreadLocals = nil
+		if hasLabelMirrors() {
+			syncLabelMirrors(t)
+		}
 	}
 }
 
+// resetThread resolves the thread again, because the thread may be replaced when the pprof labels changed.
+//
 //go:norace
-func resetThread(t *thread, threadLocals, inheritableThreadLocals *threadLocalMap) {
+func resetThread(threadLocals, inheritableThreadLocals *threadLocalMap) {
+	t := currentThread(threadLocals != nil || inheritableThreadLocals != nil)
+	if t == nil {
+		return
+	}
 	t.threadLocals = threadLocals
 	t.inheritableThreadLocals = inheritableThreadLocals
+	if hasLabelMirrors() {
+		syncLabelMirrors(t)
+	}
 }
 
 func fill[T any](a []T, fromIndex int, toIndex int, val T) {
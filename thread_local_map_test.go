@@ -51,14 +51,14 @@ func TestCreateInheritedMap_Nil(t *testing.T) {
 
 	mp := createInheritedMap()
 	assert.NotNil(t, mp)
-	getValue := entryValue[string](mp.get(tls.(*inheritableThreadLocal[string]).index))
+	getValue := entryValue[string](mp.get(tls.(*inheritableThreadLocal[string]).index, tls.(*inheritableThreadLocal[string]).version))
 	assert.Equal(t, "", getValue)
 	assert.True(t, getValue == "")
 
 	mp2 := createInheritedMap()
 	assert.NotNil(t, mp2)
 	assert.NotSame(t, mp, mp2)
-	getValue2 := entryValue[string](mp2.get(tls.(*inheritableThreadLocal[string]).index))
+	getValue2 := entryValue[string](mp2.get(tls.(*inheritableThreadLocal[string]).index, tls.(*inheritableThreadLocal[string]).version))
 	assert.Equal(t, "", getValue2)
 	assert.True(t, getValue2 == "")
 }
@@ -73,14 +73,14 @@ func TestCreateInheritedMap_Value(t *testing.T) {
 
 	mp := createInheritedMap()
 	assert.NotNil(t, mp)
-	getValue := entryValue[uint64](mp.get(tls.(*inheritableThreadLocal[uint64]).index))
+	getValue := entryValue[uint64](mp.get(tls.(*inheritableThreadLocal[uint64]).index, tls.(*inheritableThreadLocal[uint64]).version))
 	assert.NotSame(t, &value, &getValue)
 	assert.Equal(t, value, getValue)
 
 	mp2 := createInheritedMap()
 	assert.NotNil(t, mp2)
 	assert.NotSame(t, mp, mp2)
-	getValue2 := entryValue[uint64](mp2.get(tls.(*inheritableThreadLocal[uint64]).index))
+	getValue2 := entryValue[uint64](mp2.get(tls.(*inheritableThreadLocal[uint64]).index, tls.(*inheritableThreadLocal[uint64]).version))
 	assert.NotSame(t, &value, &getValue2)
 	assert.Equal(t, value, getValue2)
 }
@@ -95,14 +95,14 @@ func TestCreateInheritedMap_Struct(t *testing.T) {
 
 	mp := createInheritedMap()
 	assert.NotNil(t, mp)
-	getValue := entryValue[personCloneable](mp.get(tls.(*inheritableThreadLocal[personCloneable]).index))
+	getValue := entryValue[personCloneable](mp.get(tls.(*inheritableThreadLocal[personCloneable]).index, tls.(*inheritableThreadLocal[personCloneable]).version))
 	assert.NotSame(t, &value, &getValue)
 	assert.Equal(t, value, getValue)
 
 	mp2 := createInheritedMap()
 	assert.NotNil(t, mp2)
 	assert.NotSame(t, mp, mp2)
-	getValue2 := entryValue[personCloneable](mp2.get(tls.(*inheritableThreadLocal[personCloneable]).index))
+	getValue2 := entryValue[personCloneable](mp2.get(tls.(*inheritableThreadLocal[personCloneable]).index, tls.(*inheritableThreadLocal[personCloneable]).version))
 	assert.NotSame(t, &value, &getValue2)
 	assert.Equal(t, value, getValue2)
 }
@@ -117,14 +117,14 @@ func TestCreateInheritedMap_Pointer(t *testing.T) {
 
 	mp := createInheritedMap()
 	assert.NotNil(t, mp)
-	getValue := entryValue[*person](mp.get(tls.(*inheritableThreadLocal[*person]).index))
+	getValue := entryValue[*person](mp.get(tls.(*inheritableThreadLocal[*person]).index, tls.(*inheritableThreadLocal[*person]).version))
 	assert.Same(t, value, getValue)
 	assert.Equal(t, *value, *getValue)
 
 	mp2 := createInheritedMap()
 	assert.NotNil(t, mp2)
 	assert.NotSame(t, mp, mp2)
-	getValue2 := entryValue[*person](mp2.get(tls.(*inheritableThreadLocal[*person]).index))
+	getValue2 := entryValue[*person](mp2.get(tls.(*inheritableThreadLocal[*person]).index, tls.(*inheritableThreadLocal[*person]).version))
 	assert.Same(t, value, getValue2)
 	assert.Equal(t, *value, *getValue2)
 }
@@ -139,14 +139,14 @@ func TestCreateInheritedMap_Cloneable(t *testing.T) {
 
 	mp := createInheritedMap()
 	assert.NotNil(t, mp)
-	getValue := entryValue[*personCloneable](mp.get(tls.(*inheritableThreadLocal[*personCloneable]).index))
+	getValue := entryValue[*personCloneable](mp.get(tls.(*inheritableThreadLocal[*personCloneable]).index, tls.(*inheritableThreadLocal[*personCloneable]).version))
 	assert.NotSame(t, value, getValue)
 	assert.Equal(t, *value, *getValue)
 
 	mp2 := createInheritedMap()
 	assert.NotNil(t, mp2)
 	assert.NotSame(t, mp, mp2)
-	getValue2 := entryValue[*personCloneable](mp2.get(tls.(*inheritableThreadLocal[*personCloneable]).index))
+	getValue2 := entryValue[*personCloneable](mp2.get(tls.(*inheritableThreadLocal[*personCloneable]).index, tls.(*inheritableThreadLocal[*personCloneable]).version))
 	assert.NotSame(t, value, getValue2)
 	assert.Equal(t, *value, *getValue2)
 }
@@ -311,3 +311,45 @@ func TestFill(t *testing.T) {
 		}
 	}
 }
+
+func TestThreadLocalMap_Version(t *testing.T) {
+	mp := &threadLocalMap{}
+	mp.set(2, 0, entry("Hello"))
+	assert.Nil(t, mp.versions)
+	assert.Equal(t, "Hello", mp.get(2, 0))
+	//stale value is cleared by newer owner
+	assert.Same(t, unset, mp.get(2, 1))
+	assert.Same(t, unset, mp.get(2, 0))
+	assert.Equal(t, uint32(1), mp.version(2))
+	//older owner can not overwrite or remove
+	mp.set(2, 1, entry("World"))
+	mp.set(2, 0, entry("Hello"))
+	mp.remove(2, 0)
+	assert.Equal(t, "World", mp.get(2, 1))
+	assert.Same(t, unset, mp.get(2, 0))
+	//expand keeps versions
+	mp.set(10, 2, entry("!"))
+	assert.Equal(t, "World", mp.get(2, 1))
+	assert.Equal(t, "!", mp.get(10, 2))
+	assert.Same(t, unset, mp.get(10, 0))
+	//
+	mp.remove(2, 1)
+	assert.Same(t, unset, mp.get(2, 1))
+}
+
+func TestCreateInheritedMap_Version(t *testing.T) {
+	tls := NewInheritableThreadLocal[string]()
+	tls.Set("Hello")
+	tls.Close()
+	tls2 := NewInheritableThreadLocal[string]()
+	assert.Equal(t, tls.(*inheritableThreadLocal[string]).index, tls2.(*inheritableThreadLocal[string]).index)
+	tls2.Set("World")
+	//
+	mp := createInheritedMap()
+	assert.NotNil(t, mp)
+	index := tls2.(*inheritableThreadLocal[string]).index
+	version := tls2.(*inheritableThreadLocal[string]).version
+	assert.Equal(t, "World", entryValue[string](mp.get(index, version)))
+	assert.Same(t, unset, mp.get(index, version-1))
+	tls2.Remove()
+}
@@ -0,0 +1,105 @@
+package routine
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var threadLocalRegistry = &threadLocalRegistryMap{registrations: map[string]*threadLocalRegistration{}}
+
+type threadLocalRegistration struct {
+	name        string
+	inheritable bool
+	index       int
+	version     uint32
+	sequence    uint64
+}
+
+func (r *threadLocalRegistration) info() ThreadLocalInfo {
+	return ThreadLocalInfo{Name: r.name, Inheritable: r.inheritable}
+}
+
+// threadLocalRegistryMap holds the named ThreadLocals which are not closed.
+type threadLocalRegistryMap struct {
+	mutex         sync.RWMutex
+	registrations map[string]*threadLocalRegistration
+	sequence      uint64
+}
+
+// registerThreadLocal reserves the name and acquires an index, the name must not be used by another open ThreadLocal.
+func registerThreadLocal(name string, inheritable bool) (index int, version uint32) {
+	if len(name) == 0 {
+		panic("name can not be empty.")
+	}
+	threadLocalRegistry.mutex.Lock()
+	defer threadLocalRegistry.mutex.Unlock()
+	if _, exists := threadLocalRegistry.registrations[name]; exists {
+		panic(fmt.Sprintf("name %q is already registered.", name))
+	}
+	if inheritable {
+		index, version = acquireInheritableThreadLocalIndex()
+	} else {
+		index, version = acquireThreadLocalIndex()
+	}
+	// the indexes are reused after closed, so the order of registrations is recorded separately
+	threadLocalRegistry.sequence++
+	threadLocalRegistry.registrations[name] = &threadLocalRegistration{name: name, inheritable: inheritable, index: index, version: version, sequence: threadLocalRegistry.sequence}
+	return index, version
+}
+
+// unregisterThreadLocal releases the name, it must be called before the index released.
+func unregisterThreadLocal(name string) {
+	if len(name) == 0 {
+		return
+	}
+	threadLocalRegistry.mutex.Lock()
+	defer threadLocalRegistry.mutex.Unlock()
+	delete(threadLocalRegistry.registrations, name)
+}
+
+func threadLocalRegistrations() []*threadLocalRegistration {
+	threadLocalRegistry.mutex.RLock()
+	registrations := make([]*threadLocalRegistration, 0, len(threadLocalRegistry.registrations))
+	for _, r := range threadLocalRegistry.registrations {
+		registrations = append(registrations, r)
+	}
+	threadLocalRegistry.mutex.RUnlock()
+	sort.Slice(registrations, func(i, j int) bool {
+		if registrations[i].inheritable != registrations[j].inheritable {
+			return !registrations[i].inheritable
+		}
+		return registrations[i].sequence < registrations[j].sequence
+	})
+	return registrations
+}
+
+//go:norace
+func dumpThreadLocals(t *thread) []ThreadLocalValue {
+	if t == nil {
+		return nil
+	}
+	var values []ThreadLocalValue
+	for _, r := range threadLocalRegistrations() {
+		mp := t.threadLocals
+		if r.inheritable {
+			mp = t.inheritableThreadLocals
+		}
+		if value, ok := lookupEntry(mp, r.index, r.version); ok {
+			values = append(values, ThreadLocalValue{ThreadLocalInfo: r.info(), Value: value})
+		}
+	}
+	return values
+}
+
+// lookupEntry returns the value of the slot without discarding the stale value, so that a dump never changes the map.
+func lookupEntry(mp *threadLocalMap, index int, version uint32) (any, bool) {
+	if mp == nil || index >= len(mp.table) || mp.version(index) != version {
+		return nil, false
+	}
+	value := mp.table[index]
+	if value == unset {
+		return nil, false
+	}
+	return value, true
+}
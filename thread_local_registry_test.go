@@ -0,0 +1,71 @@
+package routine
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterThreadLocal(t *testing.T) {
+	index, version := registerThreadLocal("TestRegisterThreadLocal", true)
+	registration := threadLocalRegistry.registrations["TestRegisterThreadLocal"]
+	assert.Equal(t, &threadLocalRegistration{name: "TestRegisterThreadLocal", inheritable: true, index: index, version: version, sequence: registration.sequence}, registration)
+	assert.Equal(t, threadLocalRegistry.sequence, registration.sequence)
+	//
+	assert.PanicsWithValue(t, `name "TestRegisterThreadLocal" is already registered.`, func() {
+		registerThreadLocal("TestRegisterThreadLocal", false)
+	})
+	assert.PanicsWithValue(t, "name can not be empty.", func() {
+		registerThreadLocal("", false)
+	})
+	//
+	unregisterThreadLocal("TestRegisterThreadLocal")
+	assert.Nil(t, threadLocalRegistry.registrations["TestRegisterThreadLocal"])
+	inheritableThreadLocalIndexes.offer(index, version)
+	//
+	assert.NotPanics(t, func() {
+		unregisterThreadLocal("")
+	})
+}
+
+func TestThreadLocalRegistrations_Order(t *testing.T) {
+	tls := NewNamedThreadLocal[int]("TestThreadLocalRegistrations_Order_A")
+	tls2 := NewNamedThreadLocal[int]("TestThreadLocalRegistrations_Order_B")
+	defer tls2.Close()
+	tls.Close()
+	// the index of the closed one may be reused
+	tls3 := NewNamedThreadLocal[int]("TestThreadLocalRegistrations_Order_C")
+	defer tls3.Close()
+	var names []string
+	for _, r := range threadLocalRegistrations() {
+		if strings.HasPrefix(r.name, "TestThreadLocalRegistrations_Order_") {
+			names = append(names, r.name)
+		}
+	}
+	assert.Equal(t, []string{"TestThreadLocalRegistrations_Order_B", "TestThreadLocalRegistrations_Order_C"}, names)
+}
+
+func TestDumpThreadLocals_Nil(t *testing.T) {
+	assert.Nil(t, dumpThreadLocals(nil))
+	assert.Nil(t, dumpThreadLocals(&thread{}))
+}
+
+func TestLookupEntry(t *testing.T) {
+	_, ok := lookupEntry(nil, 0, 0)
+	assert.False(t, ok)
+	//
+	mp := &threadLocalMap{}
+	mp.set(1, 0, "hello")
+	value, ok := lookupEntry(mp, 1, 0)
+	assert.True(t, ok)
+	assert.Equal(t, "hello", value)
+	_, ok = lookupEntry(mp, 0, 0)
+	assert.False(t, ok)
+	_, ok = lookupEntry(mp, 5, 0)
+	assert.False(t, ok)
+	//
+	_, ok = lookupEntry(mp, 1, 1)
+	assert.False(t, ok)
+	assert.Equal(t, "hello", mp.table[1])
+}